@@ -3,30 +3,241 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lakshaymaurya-felt/winmole/internal/analyze"
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
 	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
 	"github.com/spf13/cobra"
 )
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze [path]",
 	Short: "Explore disk usage",
-	Long:  "Interactive disk space analyzer with visual tree view.",
-	Args:  cobra.MaximumNArgs(1),
-	Run:   runAnalyze,
+	Long: "Interactive disk space analyzer with visual tree view.\n\n" +
+		"With --top, --format, --depth or --min-size, prints the largest\n" +
+		"directories and files instead of opening the browser.",
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAnalyze,
 }
 
 func init() {
-	analyzeCmd.Flags().Int("depth", 0, "Maximum directory depth to display")
-	analyzeCmd.Flags().String("min-size", "", "Minimum size to display (e.g., 100MB)")
+	analyzeCmd.Flags().Int("depth", 0, "Maximum directory depth to report (0 = unlimited)")
+	analyzeCmd.Flags().String("min-size", "", "Minimum size to report (e.g., 100MB)")
+	analyzeCmd.Flags().Int("top", 20, "Number of directories and files to report")
+	analyzeCmd.Flags().String("format", "table", "Report format: table, json or csv")
 	analyzeCmd.Flags().StringSlice("exclude", nil, "Directories to exclude from scan")
+	analyzeCmd.Flags().Bool("refresh", false, "Ignore the cached scan and rescan everything")
+	analyzeCmd.Flags().String("export", "", "Write the scan to this file in ncdu JSON format instead of browsing it")
+	analyzeCmd.Flags().String("import", "", "Browse an ncdu or WinMole export instead of scanning")
+	analyzeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview deletes and moves from the browser without changing anything")
+	analyzeCmd.Flags().String("snapshot", "", "Keep this scan, always a full one, as a named snapshot for 'analyze diff' (timestamped if no name is given)")
+	analyzeCmd.Flags().Lookup("snapshot").NoOptDefVal = autoSnapshot
 }
 
+// autoSnapshot is the --snapshot value when no name is given.
+const autoSnapshot = "-"
+
 func runAnalyze(cmd *cobra.Command, args []string) {
-	// Determine target path (default: user home).
+	importPath, _ := cmd.Flags().GetString("import")
+	exportPath, _ := cmd.Flags().GetString("export")
+
+	// Any report flag switches to headless mode; validate before scanning.
+	headless := false
+	for _, name := range []string{"top", "format", "depth", "min-size"} {
+		headless = headless || cmd.Flags().Changed(name)
+	}
+	var opts analyze.ReportOptions
+	format, _ := cmd.Flags().GetString("format")
+	if headless {
+		var err error
+		if opts, err = reportOptions(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Browsing a fresh scan opens the TUI at once and fills it in live.
+	if importPath == "" && exportPath == "" && !headless && !cmd.Flags().Changed("snapshot") {
+		browseLive(cmd, args)
+		return
+	}
+
+	var root *analyze.DirEntry
+	if importPath != "" {
+		// Browse a scan taken elsewhere (ncdu export or WinMole file).
+		if len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: --import cannot be combined with a path to scan")
+			os.Exit(1)
+		}
+		var err error
+		root, err = analyze.ImportFile(importPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot import %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		// Machine-readable output may be piped or logged; keep stderr quiet.
+		root = scanTarget(cmd, args, headless && format != "table")
+	}
+
+	// Keep a snapshot before exporting, reporting or browsing.
+	if cmd.Flags().Changed("snapshot") {
+		name, _ := cmd.Flags().GetString("snapshot")
+		if name == autoSnapshot {
+			name = ""
+		}
+		saved, err := analyze.SaveSnapshot(root, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot save snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "  %s Saved snapshot %s\n", ui.IconSuccess, saved)
+	}
+
+	// Export instead of browsing.
+	if exportPath != "" {
+		if err := exportTree(root, exportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot export to %s: %v\n", exportPath, err)
+			os.Exit(1)
+		}
+		fmt.Printf("  %s Exported %s (%s) to %s\n",
+			ui.IconSuccess, root.Path, ui.FormatSize(root.Size), exportPath)
+		return
+	}
+
+	if headless {
+		report := analyze.BuildReport(root, opts)
+		var err error
+		switch format {
+		case "json":
+			err = report.WriteJSON(os.Stdout)
+		case "csv":
+			err = report.WriteCSV(os.Stdout)
+		default:
+			printReport(report)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Launch the TUI.
+	model := analyze.NewAnalyzeModel(root)
+	if importPath != "" {
+		model = model.WithReadOnly()
+	}
+	runBrowser(cmd, model)
+}
+
+// browseLive opens the TUI on the path in args while it is still being
+// scanned. The cache is only updated if the scan finished.
+func browseLive(cmd *cobra.Command, args []string) {
+	target := analyzeTarget(args)
+	scanner, cached := newAnalyzeScanner(cmd, target)
+
+	root, done, err := scanner.ScanLive(target, cached)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning: %v\n", err)
+		os.Exit(1)
+	}
+
+	runBrowser(cmd, analyze.NewAnalyzeModel(root).WithLiveScan(scanner, done))
+
+	select {
+	case <-done:
+		printCacheStats(scanner, cached)
+		_ = analyze.SaveCache(root, target)
+	default:
+		// Quit mid-scan: a partial tree must not become the cache.
+	}
+}
+
+// runBrowser applies the config, whitelist and dry-run mode to model and
+// runs the TUI.
+func runBrowser(cmd *cobra.Command, model analyze.AnalyzeModel) {
+	if cfg, err := config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot load config: %v\n", err)
+	} else {
+		// Batch deletes and moves honour the whitelist and dry-run mode
+		// as clean does.
+		if !cmd.Flags().Changed("dry-run") && cfg.DryRunMode {
+			dryRun = true
+		}
+		wl, wlErr := whitelist.Load(filepath.Join(cfg.ConfigDir, "whitelist.txt"))
+		if wlErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot load whitelist: %v\n", wlErr)
+		} else {
+			model = model.WithWhitelist(wl.IsWhitelisted)
+		}
+	}
+	model = model.WithDryRun(dryRun)
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// scanTarget scans the path in args (default: user home), revalidating
+// the cached tree where possible, and saves the result to the cache.
+// quiet suppresses the progress spinner.
+func scanTarget(cmd *cobra.Command, args []string, quiet bool) *analyze.DirEntry {
+	target := analyzeTarget(args)
+	scanner, cached := newAnalyzeScanner(cmd, target)
+	verb := "Scanning"
+	if cached != nil {
+		verb = "Updating"
+	}
+
+	done := make(chan struct{})
+	go func() {
+		if quiet {
+			return
+		}
+		frame := 0
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				frame = (frame + 1) % len(ui.SpinnerFrames)
+				count := scanner.ScannedCount()
+				fmt.Fprintf(os.Stderr, "\r  %s %s %s … %d entries",
+					ui.SpinnerFrames[frame], verb, target, count)
+			}
+		}
+	}()
+
+	root, err := scanner.ScanIncremental(target, cached)
+	close(done)
+	if !quiet {
+		fmt.Fprint(os.Stderr, "\r\033[K") // clear spinner line
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning: %v\n", err)
+		os.Exit(1)
+	}
+
+	printCacheStats(scanner, cached)
+
+	// Persist results for next time.
+	_ = analyze.SaveCache(root, target)
+
+	return root
+}
+
+// analyzeTarget returns the path to scan from args (default: user home),
+// exiting if it cannot be accessed.
+func analyzeTarget(args []string) string {
 	target := ""
 	if len(args) > 0 {
 		target = args[0]
@@ -45,52 +256,104 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "Error: cannot access %s: %v\n", target, err)
 		os.Exit(1)
 	}
+	return target
+}
 
-	// Parse exclude list.
+// newAnalyzeScanner creates a scanner from the flags and loads the cached
+// tree for target, unless a full rescan is requested.
+func newAnalyzeScanner(cmd *cobra.Command, target string) (*analyze.Scanner, *analyze.DirEntry) {
 	exclude, _ := cmd.Flags().GetStringSlice("exclude")
 
-	// Try loading from cache first.
-	root, err := analyze.LoadCache(target)
+	// Reuse the previous scan where directories are unchanged, unless a
+	// full rescan is requested. Snapshots always scan fully: files that
+	// grow in place (VHDX disks, databases, logs) leave their directory's
+	// mtime alone, and the cache would hide them from the diff.
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	var cached *analyze.DirEntry
+	if !refresh && !cmd.Flags().Changed("snapshot") {
+		cached, _ = analyze.LoadCache(target)
+	}
+	return analyze.NewScanner(8, exclude), cached
+}
+
+// printCacheStats reports cache reuse in debug mode.
+func printCacheStats(scanner *analyze.Scanner, cached *analyze.DirEntry) {
+	if !debug {
+		return
+	}
+	reused, read := scanner.CacheStats()
+	if cached == nil {
+		fmt.Fprintf(os.Stderr, "  cache: full scan, %d directories read\n", read)
+	} else if total := reused + read; total > 0 {
+		fmt.Fprintf(os.Stderr, "  cache: %d/%d directories reused (%.1f%% hit ratio), %d rescanned\n",
+			reused, total, float64(reused)/float64(total)*100, read)
+	}
+}
+
+// exportTree writes root to path in the ncdu JSON export format.
+func exportTree(root *analyze.DirEntry, path string) error {
+	f, err := os.Create(path)
 	if err != nil {
-		// No valid cache — run a fresh scan with a progress spinner.
-		scanner := analyze.NewScanner(8, exclude)
-
-		done := make(chan struct{})
-		go func() {
-			frame := 0
-			ticker := time.NewTicker(100 * time.Millisecond)
-			defer ticker.Stop()
-			for {
-				select {
-				case <-done:
-					return
-				case <-ticker.C:
-					frame = (frame + 1) % len(ui.SpinnerFrames)
-					count := scanner.ScannedCount()
-					fmt.Fprintf(os.Stderr, "\r  %s Scanning %s … %d entries",
-						ui.SpinnerFrames[frame], target, count)
-				}
-			}
-		}()
+		return err
+	}
+	if err := analyze.ExportNcdu(f, root, appVersion); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-		root, err = scanner.Scan(target)
-		close(done)
-		fmt.Fprint(os.Stderr, "\r\033[K") // clear spinner line
+// ─── Headless Report ─────────────────────────────────────────────────────────
+
+// reportOptions reads and validates the report flags.
+func reportOptions(cmd *cobra.Command) (analyze.ReportOptions, error) {
+	var opts analyze.ReportOptions
+	opts.Top, _ = cmd.Flags().GetInt("top")
+	opts.Depth, _ = cmd.Flags().GetInt("depth")
 
+	if opts.Top <= 0 {
+		return opts, fmt.Errorf("--top must be positive")
+	}
+	if opts.Depth < 0 {
+		return opts, fmt.Errorf("--depth must not be negative")
+	}
+	if minSize, _ := cmd.Flags().GetString("min-size"); minSize != "" {
+		size, err := parseSize(minSize)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error scanning: %v\n", err)
-			os.Exit(1)
+			return opts, fmt.Errorf("invalid --min-size value %q (e.g., 1GB)", minSize)
 		}
-
-		// Persist results for next time.
-		_ = analyze.SaveCache(root, target)
+		opts.MinSize = size
 	}
+	switch format, _ := cmd.Flags().GetString("format"); format {
+	case "table", "json", "csv":
+	default:
+		return opts, fmt.Errorf("unknown --format %q (use table, json or csv)", format)
+	}
+	return opts, nil
+}
 
-	// Launch the TUI.
-	model := analyze.NewAnalyzeModel(root)
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// printReport prints a report as two tables.
+func printReport(r analyze.Report) {
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Disk Usage", 60))
+	fmt.Printf("  %s  %s\n", r.Root, ui.BoldStyle().Render(ui.FormatSize(r.Size)))
+
+	section := func(title string, items []analyze.ReportItem) {
+		fmt.Println()
+		fmt.Println(ui.BoldStyle().Render("  " + title))
+		if len(items) == 0 {
+			fmt.Println(ui.MutedStyle().Render("  (none)"))
+			return
+		}
+		for i, it := range items {
+			fmt.Printf("  %s %10s  %s  %s\n",
+				ui.MutedStyle().Render(fmt.Sprintf("%3d.", i+1)),
+				ui.FormatSize(it.Size),
+				ui.MutedStyle().Render(fmt.Sprintf("%5.1f%%", it.Percent)),
+				it.Path)
+		}
 	}
+	section("Largest directories", r.Directories)
+	section("Largest files", r.Files)
+	fmt.Println()
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakshaymaurya-felt/winmole/internal/analyze"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var analyzeDiffCmd = &cobra.Command{
+	Use:   "diff <snapshotA> <snapshotB>",
+	Short: "Show what grew or shrank between two snapshots",
+	Long: "Compares two snapshots taken with 'wm analyze --snapshot' and lists added,\n" +
+		"removed, grown and shrunk folders by size change. Either side may also be\n" +
+		"a path to an export or cache file.",
+	Args: cobra.ExactArgs(2),
+	Run:  runAnalyzeDiff,
+}
+
+var analyzeSnapshotsCmd = &cobra.Command{
+	Use:   "snapshots",
+	Short: "List saved analyze snapshots",
+	Args:  cobra.NoArgs,
+	Run:   runAnalyzeSnapshots,
+}
+
+func init() {
+	analyzeDiffCmd.Flags().Bool("json", false, "Output the changes as JSON")
+	analyzeCmd.AddCommand(analyzeDiffCmd)
+	analyzeCmd.AddCommand(analyzeSnapshotsCmd)
+}
+
+func runAnalyzeDiff(cmd *cobra.Command, args []string) {
+	jsonMode, _ := cmd.Flags().GetBool("json")
+
+	from, fromTime, err := analyze.LoadSnapshot(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	to, toTime, err := analyze.LoadSnapshot(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	diff := analyze.DiffTrees(from, to)
+	diff.FromTime, diff.ToTime = fromTime, toTime
+
+	if jsonMode {
+		if err := diff.WriteJSON(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	p := tea.NewProgram(analyze.NewDiffModel(diff), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runAnalyzeSnapshots(cmd *cobra.Command, args []string) {
+	snaps, err := analyze.ListSnapshots()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Snapshots", 50))
+	if len(snaps) == 0 {
+		fmt.Println(ui.MutedStyle().Render("  No snapshots yet. Take one with 'wm analyze <path> --snapshot'."))
+		fmt.Println()
+		return
+	}
+	for _, s := range snaps {
+		fmt.Printf("  %-24s %s  %s\n", s.Name,
+			ui.MutedStyle().Render(s.Taken.Format("2006-01-02 15:04")),
+			ui.MutedStyle().Render(ui.FormatSize(s.Bytes)))
+	}
+	fmt.Println()
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -31,6 +34,19 @@ func init() {
 	cleanCmd.Flags().Bool("system", false, "Clean system caches only (requires admin)")
 	cleanCmd.Flags().Bool("browser", false, "Clean browser caches only")
 	cleanCmd.Flags().Bool("dev", false, "Clean developer tool caches only")
+	cleanCmd.Flags().Bool("apps", false, "Clean desktop app caches only (Teams, Slack, Discord and other Electron apps)")
+	cleanCmd.Flags().Bool("games", false, "Clean GPU shader and game launcher caches only (Steam, Epic, Battle.net)")
+	cleanCmd.Flags().Bool("store", false, "Clean Microsoft Store app caches only (LocalCache, TempState, INetCache)")
+	cleanCmd.Flags().String("free", "", "Free at least this much space, safest targets first (e.g., 20GB)")
+	cleanCmd.Flags().String("max-risk", "", "Highest risk level to clean: low, medium or high")
+	cleanCmd.Flags().Bool("native", false, "Prune developer caches with their own tools (npm, pip, dotnet, go, pnpm, yarn, cargo) when installed")
+	cleanCmd.Flags().Int("keep-versions", 0, "Newest versions to keep of versioned dev caches (Gradle, VS Code extensions, JetBrains, rustup)")
+	cleanCmd.Flags().String("privacy", "", "Also delete browser privacy data: history, cookies, downloads, forms, sessions or all (high risk)")
+	cleanCmd.Flags().Bool("close-browsers", false, "Offer to close your running browsers so their caches can be cleaned (ignored with --all-users)")
+	cleanCmd.Flags().Bool("all-users", false, "Clean every user profile on this machine (requires admin)")
+	cleanCmd.Flags().String("root", "", "Clean the Windows installation under this directory (e.g. a mounted image at E:\\) instead of the live system")
+	cleanCmd.Flags().Bool("prune-empty", false, "After cleaning, remove folders left empty under each cleaned cache")
+	cleanCmd.Flags().Int("collapse", 0, "Treat folders with at least N files as one item (fewer, larger deletes on huge caches)")
 }
 
 // ─── Main Entry Point ────────────────────────────────────────────────────────
@@ -67,14 +83,102 @@ func runClean(cmd *cobra.Command, args []string) {
 	systemFlag, _ := cmd.Flags().GetBool("system")
 	browserFlag, _ := cmd.Flags().GetBool("browser")
 	devFlag, _ := cmd.Flags().GetBool("dev")
+	appsFlag, _ := cmd.Flags().GetBool("apps")
+	storeFlag, _ := cmd.Flags().GetBool("store")
+	gamesFlag, _ := cmd.Flags().GetBool("games")
+
+	// Privacy data is opt-in and selected kind by kind.
+	privacyFlag, _ := cmd.Flags().GetString("privacy")
+	privacyKinds, privacyErr := clean.ParsePrivacyKinds(privacyFlag)
+	if privacyErr != nil {
+		fmt.Println(ui.ErrorStyle().Render(
+			fmt.Sprintf("  %s %v", ui.IconError, privacyErr)))
+		os.Exit(1)
+	}
 
-	// Default to all if no category specified.
-	if !allFlag && !userFlag && !systemFlag && !browserFlag && !devFlag {
+	// Default to all if no category specified (--privacy alone counts as one).
+	if !allFlag && !userFlag && !systemFlag && !browserFlag && !devFlag && !appsFlag && !storeFlag && !gamesFlag && len(privacyKinds) == 0 {
 		allFlag = true
 	}
 
+	collapse, _ := cmd.Flags().GetInt("collapse")
+	nativeFlag, _ := cmd.Flags().GetBool("native")
+	closeBrowsers, _ := cmd.Flags().GetBool("close-browsers")
+
+	pruneEmpty, _ := cmd.Flags().GetBool("prune-empty")
+	if !cmd.Flags().Changed("prune-empty") {
+		pruneEmpty = cfg.PruneEmptyDirs
+	}
+
+	keepVersions, _ := cmd.Flags().GetInt("keep-versions")
+	if !cmd.Flags().Changed("keep-versions") {
+		keepVersions = cfg.KeepVersions
+	}
+
+	// Parse risk ceiling (flag overrides config) and free-space goal.
+	maxRiskFlag, _ := cmd.Flags().GetString("max-risk")
+	if !cmd.Flags().Changed("max-risk") {
+		maxRiskFlag = cfg.MaxRisk
+	}
+	maxRisk, riskErr := config.ParseRiskLevel(maxRiskFlag)
+	if riskErr != nil {
+		fmt.Println(ui.ErrorStyle().Render(
+			fmt.Sprintf("  %s %v", ui.IconError, riskErr)))
+		os.Exit(1)
+	}
+
+	var freeGoal int64
+	if freeFlag, _ := cmd.Flags().GetString("free"); freeFlag != "" {
+		goal, sizeErr := parseSize(freeFlag)
+		if sizeErr != nil || goal <= 0 {
+			fmt.Println(ui.ErrorStyle().Render(
+				fmt.Sprintf("  %s Invalid --free value %q (e.g., 20GB)", ui.IconError, freeFlag)))
+			os.Exit(1)
+		}
+		freeGoal = goal
+	}
+
 	isAdmin := core.IsElevated()
 
+	// --root rebases every system path onto another Windows installation
+	// and cleans each profile found in its Users folder.
+	rootFlag, _ := cmd.Flags().GetString("root")
+	if rootFlag != "" {
+		if rootErr := config.SetRoot(rootFlag); rootErr != nil {
+			fmt.Println(ui.ErrorStyle().Render(
+				fmt.Sprintf("  %s %v", ui.IconError, rootErr)))
+			os.Exit(1)
+		}
+	}
+	offline := config.Root() != ""
+
+	// --all-users re-resolves every per-user target for each real profile.
+	allUsers, _ := cmd.Flags().GetBool("all-users")
+	var profiles []core.UserProfile
+	if offline {
+		var profErr error
+		profiles, profErr = core.ProfilesUnder(config.Rebase(`C:\Users`))
+		if profErr != nil {
+			fmt.Println(ui.ErrorStyle().Render(
+				fmt.Sprintf("  %s %v", ui.IconError, profErr)))
+			os.Exit(1)
+		}
+		allUsers = true
+	} else if allUsers {
+		if !isAdmin {
+			fmt.Println(ui.ErrorStyle().Render(
+				fmt.Sprintf("  %s --all-users requires an elevated (administrator) prompt", ui.IconError)))
+			os.Exit(1)
+		}
+		var profErr error
+		profiles, profErr = core.UserProfiles()
+		if profErr != nil {
+			fmt.Println(ui.ErrorStyle().Render(
+				fmt.Sprintf("  %s %v", ui.IconError, profErr)))
+			os.Exit(1)
+		}
+	}
+
 	// ── Header ───────────────────────────────────────────────────────────
 	fmt.Println()
 	fmt.Println(ui.SectionHeader("Deep Clean", 55))
@@ -83,6 +187,22 @@ func runClean(cmd *cobra.Command, args []string) {
 		fmt.Println(ui.WarningStyle().Render(
 			fmt.Sprintf("  %s  DRY RUN MODE — no files will be deleted", ui.IconWarning)))
 	}
+	if maxRisk != config.RiskHigh {
+		fmt.Println(ui.MutedStyle().Render(
+			fmt.Sprintf("  Max risk: %s — riskier targets will be skipped", maxRisk)))
+	}
+	if offline {
+		fmt.Println(ui.MutedStyle().Render(
+			fmt.Sprintf("  Root: %s — live-system steps (Recycle Bin, Go cache, running browsers) skipped", config.Root())))
+	}
+	if allUsers {
+		names := make([]string, len(profiles))
+		for i, p := range profiles {
+			names[i] = p.Name
+		}
+		fmt.Println(ui.MutedStyle().Render(
+			fmt.Sprintf("  All users: %s", strings.Join(names, ", "))))
+	}
 	if !isAdmin && (allFlag || systemFlag) {
 		fmt.Println(ui.WarningStyle().Render(
 			fmt.Sprintf("  %s  Not running as admin — system items will be skipped", ui.IconWarning)))
@@ -95,39 +215,62 @@ func runClean(cmd *cobra.Command, args []string) {
 
 	var allResults []clean.ScanResult
 
-	// User caches: use config targets via ScanAll.
-	if allFlag || userFlag {
-		userTargets := config.GetTargetsByCategory("user")
-		userResults := clean.ScanAll(userTargets, wl, isAdmin)
-		allResults = append(allResults, userResults...)
+	scope := cleanScope{
+		user:         allFlag || userFlag,
+		browser:      allFlag || browserFlag,
+		apps:         allFlag || appsFlag,
+		store:        allFlag || storeFlag,
+		games:        allFlag || gamesFlag,
+		dev:          allFlag || devFlag,
+		system:       allFlag || systemFlag,
+		privacyKinds: privacyKinds,
+		keepVersions: keepVersions,
+		collapse:     collapse,
+		isAdmin:      isAdmin,
+		procs:        clean.DefaultProcessLister,
+	}
+	if offline {
+		// Processes of the live system say nothing about the image.
+		scope.procs = nil
 	}
 
-	// Browser caches: use specialized multi-profile scanner.
-	if allFlag || browserFlag {
-		browserItems := clean.ScanBrowserCaches(wl)
-		if len(browserItems) > 0 {
-			browserGroups := groupItemsByDescription(browserItems)
-			for name, items := range browserGroups {
-				allResults = append(allResults, clean.ItemsToResult(name, items))
-			}
+	// Per-user targets: the current user, or every profile with --all-users.
+	// Old rustup toolchains are uninstalled through rustup and, with
+	// --native, caches whose tools are installed are pruned by those tools
+	// (current user only; the tools run as this account).
+	var nativeSteps []clean.NativeCleanup
+	profileWLs := make(map[string]*whitelist.Whitelist, len(profiles))
+	if allUsers {
+		for _, p := range profiles {
+			userWL := profileWhitelist(p, wl)
+			profileWLs[p.Name] = userWL
+			core.WithProfileEnv(p, func() {
+				spinner.UpdateMessage(fmt.Sprintf("Scanning %s...", p.Name))
+				userResults, _ := scanProfile(spinner, scope, userWL, false, false)
+				for i := range userResults {
+					userResults[i].User = p.Name
+				}
+				allResults = append(allResults, userResults...)
+			})
 		}
+	} else {
+		var userResults []clean.ScanResult
+		userResults, nativeSteps = scanProfile(spinner, scope, wl, true, nativeFlag)
+		allResults = append(allResults, userResults...)
 	}
 
-	// Developer caches: use specialized scanner for safety.
-	if allFlag || devFlag {
-		devItems := clean.ScanDevCaches(wl)
-		if len(devItems) > 0 {
-			devGroups := groupItemsByDescription(devItems)
-			for name, items := range devGroups {
-				allResults = append(allResults, clean.ItemsToResult(name, items))
-			}
+	// Steam caches in every library listed in libraryfolders.vdf. Steam is
+	// installed per machine, so it is scanned once even with --all-users.
+	if scope.games {
+		for name, group := range groupItemsByDescription(clean.ScanSteamCaches(wl)) {
+			allResults = append(allResults, clean.ItemsToResult(name, group))
 		}
 	}
 
 	// System caches: use config targets via ScanAll (admin-gated).
 	if allFlag || systemFlag {
 		systemTargets := config.GetTargetsByCategory("system")
-		systemResults := clean.ScanAll(systemTargets, wl, isAdmin)
+		systemResults := streamScan(spinner, "system caches", systemTargets, wl, isAdmin, collapse)
 		allResults = append(allResults, systemResults...)
 
 		// Memory dumps (separate scan).
@@ -135,23 +278,18 @@ func runClean(cmd *cobra.Command, args []string) {
 		if len(dumpItems) > 0 {
 			allResults = append(allResults, clean.ItemsToResult("MemoryDumps", dumpItems))
 		}
-
-		// WER user-level reports (no admin needed).
-		werItems := clean.ScanWERUserReports(wl)
-		if len(werItems) > 0 {
-			allResults = append(allResults, clean.ItemsToResult("WER User Reports", werItems))
-		}
 	}
 
-	// Recycle Bin (user category, via Shell API).
+	// Recycle Bin (user category, via Shell API). The Shell API and the go
+	// tool only see the live system, so both are skipped under --root.
 	var recycleBinSize int64
-	if allFlag || userFlag {
+	if (allFlag || userFlag) && !offline {
 		recycleBinSize, _ = clean.ScanRecycleBin()
 	}
 
 	// Go module cache size.
 	var goModSize int64
-	if allFlag || devFlag {
+	if (allFlag || devFlag) && !offline {
 		goModSize = clean.GoModCacheSize()
 	}
 
@@ -163,9 +301,60 @@ func runClean(cmd *cobra.Command, args []string) {
 
 	spinner.Stop("Scan complete")
 
+	// ── Running Browsers ─────────────────────────────────────────────────
+	// Caches of open browsers are held back unless the user lets us close
+	// the browser first.
+	var deferred []clean.ScanResult
+	if running := clean.InUseBrowsers(allResults); len(running) > 0 {
+		names := strings.Join(running, ", ")
+		// Other users' browsers are never closed: under --all-users the
+		// deferred caches may belong to someone else's open session.
+		if closeBrowsers && !dryRun && !allUsers {
+			fmt.Println()
+			ok, _ := ui.Confirm(fmt.Sprintf("  Close %s now so its caches can be cleaned?", names))
+			if ok {
+				closed := clean.CloseBrowsers(running, clean.DefaultRunner, clean.DefaultProcessLister, 10*time.Second)
+				allResults = clean.ReleaseInUse(allResults, closed)
+			}
+		}
+		allResults, deferred = clean.SplitInUse(allResults)
+		displayDeferred(deferred, closeBrowsers, allUsers)
+	}
+
+	// ── Apply Risk Ceiling ───────────────────────────────────────────────
+	allResults = clean.FilterByMaxRisk(allResults, maxRisk)
+	if !withinMaxRisk("RecycleBin", maxRisk) {
+		recycleBinSize = 0
+	}
+	if !withinMaxRisk("GoModCache", maxRisk) {
+		goModSize = 0
+	}
+	if !withinMaxRisk("WindowsOld", maxRisk) {
+		windowsOldSize = 0
+	}
+	nativeSteps = filterNative(nativeSteps, func(nc clean.NativeCleanup) bool {
+		return config.RiskRank(nc.RiskLevel) <= config.RiskRank(maxRisk)
+	})
+
+	// ── Goal Planning ────────────────────────────────────────────────────
+	// The full pool is kept so the plan can be redone if high-risk
+	// targets are declined at the confirmation below.
+	pool := cleanSelection{allResults, nativeSteps, recycleBinSize, goModSize, windowsOldSize}
+	var plan *clean.GoalPlan
+	if freeGoal > 0 {
+		sel, p := planGoal(pool, freeGoal, maxRisk)
+		plan = &p
+		allResults, nativeSteps = sel.results, sel.native
+		recycleBinSize, goModSize, windowsOldSize = sel.recycleBin, sel.goMod, sel.windowsOld
+	}
+
 	// ── Calculate Totals ─────────────────────────────────────────────────
-	totalSize := clean.TotalSizeAll(allResults) + recycleBinSize + goModSize + windowsOldSize
-	totalItems := clean.TotalItemCount(allResults)
+	totalSize, totalItems := cleanSelection{allResults, nativeSteps, recycleBinSize, goModSize, windowsOldSize}.totals()
+
+	if totalSize == 0 && plan != nil {
+		displayGoalPlan(*plan)
+		return
+	}
 
 	if totalSize == 0 {
 		fmt.Println()
@@ -176,35 +365,43 @@ func runClean(cmd *cobra.Command, args []string) {
 	}
 
 	// ── Display Results ──────────────────────────────────────────────────
-	displayCleanResults(allResults, recycleBinSize, goModSize, windowsOldSize)
+	displayCleanResults(allResults, nativeSteps, recycleBinSize, goModSize, windowsOldSize)
+	displayTotal(totalSize, totalItems)
 
-	fmt.Println(ui.Divider(55))
-	fmt.Printf("  %-35s %s  %s\n",
-		ui.BoldStyle().Render("Total"),
-		ui.FormatSize(totalSize),
-		ui.MutedStyle().Render(fmt.Sprintf("(%d items)", totalItems)),
-	)
-	fmt.Println()
+	if plan != nil {
+		displayGoalPlan(*plan)
+	}
+
+	displayPrivacyWarnings(allResults, privacyKinds)
 
 	// ── Dry Run: Export and Exit ─────────────────────────────────────────
 	if dryRun {
 		drc := core.NewDryRunContext()
 		for _, r := range allResults {
-			for _, item := range r.Items {
-				drc.Add(item.Path, item.Size, item.Category)
-			}
+			r.EachItem(func(item clean.CleanItem) bool {
+				drc.AddWithRisk(item.Path, item.Size, item.Category, itemRisk(item))
+				return true
+			})
 		}
 		if recycleBinSize > 0 {
-			drc.Add("Recycle Bin (Shell API)", recycleBinSize, "user")
+			drc.AddWithRisk("Recycle Bin (Shell API)", recycleBinSize, "user", targetRiskLevel("RecycleBin"))
 		}
 		if goModSize > 0 {
-			drc.Add("Go module cache", goModSize, "dev")
+			drc.AddWithRisk("Go module cache", goModSize, "dev", targetRiskLevel("GoModCache"))
 		}
 		if windowsOldSize > 0 {
-			drc.Add(`C:\Windows.old`, windowsOldSize, "system")
+			drc.AddWithRisk(config.Rebase(`C:\Windows.old`), windowsOldSize, "system", targetRiskLevel("WindowsOld"))
+		}
+		for _, nc := range nativeSteps {
+			drc.AddWithRisk(nc.Pruner.CommandLine(), nc.Size, "dev", nc.RiskLevel)
 		}
 
 		drc.PrintSummary()
+		if highRisk := highRiskSummary(allResults, windowsOldSize); len(highRisk) > 0 && plan != nil {
+			fmt.Println(ui.MutedStyle().Render(fmt.Sprintf(
+				"  Includes high-risk targets (%s); declining them on a real run redoes the plan at max risk %s.",
+				strings.Join(highRisk, ", "), config.RiskMedium)))
+		}
 
 		exportPath := filepath.Join(cfg.ConfigDir, "clean-list.txt")
 		if exportErr := drc.ExportToFile(exportPath); exportErr != nil {
@@ -227,6 +424,50 @@ func runClean(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	// ── High-Risk Confirmation ───────────────────────────────────────────
+	// High-risk targets need a typed "yes"; declining drops only them, and
+	// a --free plan is redone so the goal is still met where possible.
+	if highRisk := highRiskSummary(allResults, windowsOldSize); len(highRisk) > 0 {
+		confirmedHigh, _ := ui.DangerConfirm(fmt.Sprintf(
+			"This cleanup includes HIGH-RISK targets: %s. They cannot be restored.",
+			strings.Join(highRisk, ", ")))
+		if !confirmedHigh && plan != nil {
+			// Make up for the declined targets from the medium- and
+			// low-risk ones, show the new selection and confirm it.
+			sel, p := planGoal(pool, freeGoal, config.RiskMedium)
+			p.Excluded = nil // declined just now, not held back by --max-risk
+			allResults, nativeSteps = sel.results, sel.native
+			recycleBinSize, goModSize, windowsOldSize = sel.recycleBin, sel.goMod, sel.windowsOld
+			fmt.Println(ui.MutedStyle().Render(fmt.Sprintf(
+				"  High-risk targets declined — plan redone with max risk %s:", config.RiskMedium)))
+			fmt.Println()
+
+			totalSize, totalItems = sel.totals()
+			if totalSize == 0 {
+				displayGoalPlan(p)
+				return
+			}
+			displayCleanResults(allResults, nativeSteps, recycleBinSize, goModSize, windowsOldSize)
+			displayTotal(totalSize, totalItems)
+			displayGoalPlan(p)
+
+			confirmed, confirmErr = ui.Confirm(
+				fmt.Sprintf("  Proceed to free %s?", core.FormatSize(totalSize)))
+			if confirmErr != nil || !confirmed {
+				fmt.Println(ui.MutedStyle().Render("  Cleanup cancelled."))
+				fmt.Println()
+				return
+			}
+		} else if !confirmedHigh {
+			allResults = clean.FilterByMaxRisk(allResults, config.RiskMedium)
+			windowsOldSize = 0
+			totalSize, _ = cleanSelection{allResults, nativeSteps, recycleBinSize, goModSize, windowsOldSize}.totals()
+			fmt.Println(ui.MutedStyle().Render(fmt.Sprintf(
+				"  High-risk targets skipped — freeing %s at max risk %s.", core.FormatSize(totalSize), config.RiskMedium)))
+			fmt.Println()
+		}
+	}
+
 	// ── Initialize Logger ────────────────────────────────────────────────
 	logger, logErr := core.NewLogger(cfg.LogFile)
 	if logErr != nil {
@@ -247,10 +488,13 @@ func runClean(cmd *cobra.Command, args []string) {
 	var totalFreed int64
 	var totalCleaned int
 	var errCount int
+	var heldBack int
 
-	// Delete all scanned items via SafeDelete.
+	// Delete all scanned items via SafeDelete. Streamed targets are walked
+	// again and each item deleted as it arrives; files that were not in the
+	// preview are held back.
 	for _, r := range allResults {
-		for _, item := range r.Items {
+		heldBack += r.EachItem(func(item clean.CleanItem) bool {
 			cleanSpinner.UpdateMessage(
 				fmt.Sprintf("Cleaning %s...", filepath.Base(item.Path)))
 
@@ -261,17 +505,18 @@ func runClean(cmd *cobra.Command, args []string) {
 					fmt.Printf("\n  %s %v\n", ui.IconError, delErr)
 				}
 				if logger != nil {
-					logger.Log("DELETE", item.Path, 0, delErr)
+					logger.LogWithRisk("DELETE", item.Path, 0, itemRisk(item), delErr)
 				}
-				continue
+				return true
 			}
 
 			totalFreed += freed
-			totalCleaned++
+			totalCleaned += item.Files()
 			if logger != nil {
-				logger.Log("DELETE", item.Path, freed, nil)
+				logger.LogWithRisk("DELETE", item.Path, freed, itemRisk(item), nil)
 			}
-		}
+			return true
+		})
 	}
 
 	// Empty Recycle Bin.
@@ -280,17 +525,39 @@ func runClean(cmd *cobra.Command, args []string) {
 		if rbErr := clean.EmptyRecycleBin(false); rbErr != nil {
 			errCount++
 			if logger != nil {
-				logger.Log("EMPTY_RECYCLE_BIN", "RecycleBin", 0, rbErr)
+				logger.LogWithRisk("EMPTY_RECYCLE_BIN", "RecycleBin", 0, targetRiskLevel("RecycleBin"), rbErr)
 			}
 		} else {
 			totalFreed += recycleBinSize
 			totalCleaned++
 			if logger != nil {
-				logger.Log("EMPTY_RECYCLE_BIN", "RecycleBin", recycleBinSize, nil)
+				logger.LogWithRisk("EMPTY_RECYCLE_BIN", "RecycleBin", recycleBinSize, targetRiskLevel("RecycleBin"), nil)
 			}
 		}
 	}
 
+	// Native developer cache pruning.
+	for _, nc := range nativeSteps {
+		cleanSpinner.UpdateMessage(fmt.Sprintf("Running %s...", nc.Pruner.CommandLine()))
+		freed, nErr := nc.Pruner.Prune(clean.DefaultRunner)
+		if nErr != nil {
+			errCount++
+			if debugMode {
+				fmt.Printf("\n  %s %v\n", ui.IconError, nErr)
+			}
+			if logger != nil {
+				logger.LogWithRisk("NATIVE_PRUNE", nc.Pruner.CommandLine(), 0, nc.RiskLevel, nErr)
+			}
+			continue
+		}
+		// Prune commands may keep part of the cache; report what went.
+		totalFreed += freed
+		totalCleaned += nc.ItemCount
+		if logger != nil {
+			logger.LogWithRisk("NATIVE_PRUNE", nc.Pruner.CommandLine(), freed, nc.RiskLevel, nil)
+		}
+	}
+
 	// Go module cache.
 	if goModSize > 0 {
 		cleanSpinner.UpdateMessage("Cleaning Go module cache...")
@@ -298,38 +565,47 @@ func runClean(cmd *cobra.Command, args []string) {
 		if goErr != nil {
 			errCount++
 			if logger != nil {
-				logger.Log("GO_CLEAN_MODCACHE", "go mod cache", 0, goErr)
+				logger.LogWithRisk("GO_CLEAN_MODCACHE", "go mod cache", 0, targetRiskLevel("GoModCache"), goErr)
 			}
 		} else {
 			totalFreed += freed
 			totalCleaned++
 			if logger != nil {
-				logger.Log("GO_CLEAN_MODCACHE", "go mod cache", freed, nil)
+				logger.LogWithRisk("GO_CLEAN_MODCACHE", "go mod cache", freed, targetRiskLevel("GoModCache"), nil)
 			}
 		}
 	}
 
-	// Windows.old (requires DangerConfirm inside CleanWindowsOld).
+	// Windows.old (already confirmed via the high-risk DangerConfirm).
 	if windowsOldSize > 0 {
-		cleanSpinner.Stop("Pausing for confirmation...")
+		cleanSpinner.UpdateMessage("Removing Windows.old...")
 
-		freed, woErr := clean.CleanWindowsOld(false)
+		freed, woErr := clean.RemoveWindowsOld(false)
 		if woErr != nil {
 			errCount++
 			if logger != nil {
-				logger.Log("DELETE_WINDOWS_OLD", `C:\Windows.old`, 0, woErr)
+				logger.LogWithRisk("DELETE_WINDOWS_OLD", config.Rebase(`C:\Windows.old`), 0, targetRiskLevel("WindowsOld"), woErr)
 			}
 		} else if freed > 0 {
 			totalFreed += freed
 			totalCleaned++
 			if logger != nil {
-				logger.Log("DELETE_WINDOWS_OLD", `C:\Windows.old`, freed, nil)
+				logger.LogWithRisk("DELETE_WINDOWS_OLD", config.Rebase(`C:\Windows.old`), freed, targetRiskLevel("WindowsOld"), nil)
 			}
 		}
+	}
 
-		// Restart spinner for remaining work.
-		cleanSpinner = ui.NewInlineSpinner()
-		cleanSpinner.Start("Finishing cleanup...")
+	// Empty folder skeletons left under the cleaned roots.
+	var pruned clean.PruneResult
+	if pruneEmpty {
+		cleanSpinner.UpdateMessage("Removing empty folders...")
+		pruned = pruneEmptyDirs(allResults, profiles, profileWLs, wl)
+		if logger != nil {
+			for _, dir := range pruned.Removed {
+				logger.Log("RMDIR", dir, 0, nil)
+			}
+			logger.LogPrune(len(pruned.Removed), pruned.Failed)
+		}
 	}
 
 	cleanSpinner.Stop("Cleanup complete")
@@ -352,22 +628,99 @@ func runClean(cmd *cobra.Command, args []string) {
 		fmt.Sprintf("  %s  Freed %s across %d items",
 			ui.IconSuccess, core.FormatSize(totalFreed), totalCleaned)))
 
+	if len(pruned.Removed) > 0 {
+		fmt.Println(ui.MutedStyle().Render(
+			fmt.Sprintf("  Removed %d empty folders", len(pruned.Removed))))
+	}
 	if errCount > 0 {
 		fmt.Println(ui.WarningStyle().Render(
 			fmt.Sprintf("  %s  %d items skipped (locked or access denied)",
 				ui.IconWarning, errCount)))
 	}
+	if heldBack > 0 {
+		fmt.Println(ui.MutedStyle().Render(
+			fmt.Sprintf("  %d new files appeared after the preview and were left in place; re-run to review them", heldBack)))
+	}
+	if len(deferred) > 0 {
+		fmt.Println(ui.WarningStyle().Render(
+			fmt.Sprintf("  %s  %s in %d items deferred (%s running)",
+				ui.IconWarning, core.FormatSize(clean.TotalSizeAll(deferred)),
+				clean.TotalItemCount(deferred), strings.Join(clean.InUseBrowsers(deferred), ", "))))
+	}
 	fmt.Println()
 }
 
 // ─── Display Helpers ─────────────────────────────────────────────────────────
 
+// displayPrivacyWarnings explains what each selected privacy kind deletes,
+// if any privacy data is part of the cleanup, and what it does not cover.
+func displayPrivacyWarnings(results []clean.ScanResult, kinds []string) {
+	var noted bool
+	for _, kind := range kinds {
+		if note := clean.PrivacyNote(kind); note != "" {
+			fmt.Println(ui.MutedStyle().Render(fmt.Sprintf("  %s", note)))
+			noted = true
+		}
+	}
+	if len(clean.GroupByCategory(results)["privacy"]) == 0 {
+		if noted {
+			fmt.Println()
+		}
+		return
+	}
+
+	for _, kind := range kinds {
+		fmt.Println(ui.WarningStyle().Render(
+			fmt.Sprintf("  %s  %s", ui.IconWarning, clean.PrivacyWarning(kind))))
+	}
+	fmt.Println()
+}
+
+// displayDeferred lists browser caches held back because their browser is
+// running.
+func displayDeferred(deferred []clean.ScanResult, closeBrowsers, allUsers bool) {
+	if len(deferred) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(ui.WarningStyle().Render(
+		fmt.Sprintf("  %s  Skipping caches of running browsers:", ui.IconWarning)))
+	for _, r := range deferred {
+		fmt.Printf("    %-31s  %10s  %s\n",
+			r.Key(),
+			ui.FormatSize(r.TotalSize),
+			ui.MutedStyle().Render("(browser running)"),
+		)
+	}
+	switch {
+	case closeBrowsers && allUsers:
+		fmt.Println(ui.MutedStyle().Render(
+			"  --close-browsers is ignored with --all-users; close the browsers and re-run."))
+	case !closeBrowsers:
+		fmt.Println(ui.MutedStyle().Render(
+			"  Close the browser and re-run, or use --close-browsers."))
+	}
+}
+
 // displayCleanResults prints scan results grouped by high-level category.
 func displayCleanResults(
 	results []clean.ScanResult,
+	native []clean.NativeCleanup,
 	recycleBinSize, goModSize, windowsOldSize int64,
 ) {
-	groups := clean.GroupByCategory(results)
+	// With --all-users, per-user results are listed by user after the
+	// shared (current user and system) categories.
+	var shared []clean.ScanResult
+	byUser := make(map[string][]clean.ScanResult)
+	for _, r := range results {
+		if r.User == "" {
+			shared = append(shared, r)
+		} else {
+			byUser[r.User] = append(byUser[r.User], r)
+		}
+	}
+	groups := clean.GroupByCategory(shared)
 
 	type categoryDef struct {
 		key   string
@@ -377,6 +730,10 @@ func displayCleanResults(
 	categories := []categoryDef{
 		{"user", "User Caches"},
 		{"browser", "Browser Caches"},
+		{"privacy", "Browser Privacy"},
+		{"apps", "Desktop Apps"},
+		{"store", "Store Apps"},
+		{"games", "Games & Shaders"},
 		{"dev", "Developer Tools"},
 		{"system", "System"},
 	}
@@ -392,7 +749,7 @@ func displayCleanResults(
 		case "user":
 			hasExtra = recycleBinSize > 0
 		case "dev":
-			hasExtra = goModSize > 0 || clean.IsDockerAvailable()
+			hasExtra = goModSize > 0 || len(native) > 0 || clean.IsDockerAvailable()
 		case "system":
 			hasExtra = windowsOldSize > 0
 		}
@@ -411,10 +768,11 @@ func displayCleanResults(
 			})
 
 			for _, r := range groupResults {
-				fmt.Printf("    %-31s  %10s  %s\n",
+				fmt.Printf("    %-31s  %10s  %s  %s\n",
 					r.Category,
 					ui.FormatSize(r.TotalSize),
 					ui.MutedStyle().Render(fmt.Sprintf("(%d items)", r.ItemCount)),
+					riskBadge(r.RiskLevel),
 				)
 			}
 		}
@@ -423,17 +781,27 @@ func displayCleanResults(
 		switch cat.key {
 		case "user":
 			if recycleBinSize > 0 {
-				fmt.Printf("    %-31s  %10s\n",
+				fmt.Printf("    %-31s  %10s  %s\n",
 					"Recycle Bin",
 					ui.FormatSize(recycleBinSize),
+					riskBadge(targetRiskLevel("RecycleBin")),
 				)
 			}
 		case "dev":
+			for _, nc := range native {
+				fmt.Printf("    %-31s  %10s  %s  %s\n",
+					nc.PlanName(),
+					ui.FormatSize(nc.Size),
+					ui.MutedStyle().Render("("+nc.Pruner.CommandLine()+")"),
+					riskBadge(nc.RiskLevel),
+				)
+			}
 			if goModSize > 0 {
-				fmt.Printf("    %-31s  %10s  %s\n",
+				fmt.Printf("    %-31s  %10s  %s  %s\n",
 					"Go module cache",
 					ui.FormatSize(goModSize),
 					ui.MutedStyle().Render("(go clean -modcache)"),
+					riskBadge(targetRiskLevel("GoModCache")),
 				)
 			}
 			if clean.IsDockerAvailable() {
@@ -445,16 +813,400 @@ func displayCleanResults(
 			}
 		case "system":
 			if windowsOldSize > 0 {
-				fmt.Printf("    %-31s  %10s  %s\n",
+				fmt.Printf("    %-31s  %10s  %s  %s\n",
 					"Windows.old",
 					ui.FormatSize(windowsOldSize),
 					ui.WarningStyle().Render("(requires confirmation)"),
+					riskBadge(targetRiskLevel("WindowsOld")),
 				)
 			}
 		}
 
 		fmt.Println()
 	}
+
+	users := make([]string, 0, len(byUser))
+	for name := range byUser {
+		users = append(users, name)
+	}
+	sort.Strings(users)
+
+	for _, name := range users {
+		userResults := byUser[name]
+		sort.Slice(userResults, func(i, j int) bool {
+			return userResults[i].Category < userResults[j].Category
+		})
+
+		fmt.Println(ui.SectionHeader("User: "+name, 55))
+		for _, r := range userResults {
+			fmt.Printf("    %-31s  %10s  %s  %s\n",
+				r.Category,
+				ui.FormatSize(r.TotalSize),
+				ui.MutedStyle().Render(fmt.Sprintf("(%d items)", r.ItemCount)),
+				riskBadge(r.RiskLevel),
+			)
+		}
+		fmt.Println()
+	}
+}
+
+// cleanScope selects the per-user categories a profile scan covers.
+type cleanScope struct {
+	user, browser, apps, store, games, dev, system bool
+
+	privacyKinds []string
+	keepVersions int
+	collapse     int
+	isAdmin      bool
+
+	// procs detects running browsers; nil skips detection (--root).
+	procs clean.ProcessLister
+}
+
+// scanProfile runs every per-user scan in scope for the profile whose
+// environment is active (see core.WithProfileEnv). own is true for the
+// current user's profile, whose tools may be run: old rustup toolchains
+// are then uninstalled through rustup, and with native, dev caches whose
+// tools are installed are returned as native cleanups too.
+func scanProfile(
+	spinner *ui.InlineSpinner,
+	scope cleanScope,
+	wl *whitelist.Whitelist,
+	own bool,
+	native bool,
+) ([]clean.ScanResult, []clean.NativeCleanup) {
+	var results []clean.ScanResult
+	addItems := func(items []clean.CleanItem) {
+		for name, group := range groupItemsByDescription(items) {
+			results = append(results, clean.ItemsToResult(name, group))
+		}
+	}
+
+	// User caches: use config targets via ScanAll.
+	if scope.user {
+		userTargets := config.GetTargetsByCategory("user")
+		results = append(results, streamScan(spinner, "user caches", userTargets, wl, scope.isAdmin, scope.collapse)...)
+	}
+
+	// Browser caches: use specialized multi-profile scanner.
+	if scope.browser {
+		addItems(clean.ScanBrowserCaches(wl, scope.procs))
+	}
+
+	// Browser privacy data: only the kinds explicitly requested.
+	if len(scope.privacyKinds) > 0 {
+		addItems(clean.ScanBrowserPrivacy(scope.privacyKinds, wl, scope.procs))
+	}
+
+	// Desktop app caches: Electron apps discovered by their cache layout.
+	if scope.apps {
+		addItems(clean.ScanAppCaches(wl))
+	}
+
+	// Microsoft Store app caches: per-package cache and temp folders.
+	if scope.store {
+		addItems(clean.ScanStoreAppCaches(wl))
+	}
+
+	// Game caches: GPU shader and launcher targets, plus Steam's per-user
+	// web cache. The Steam install itself is scanned once, in runClean.
+	if scope.games {
+		gameTargets := config.GetTargetsByCategory("games")
+		results = append(results, streamScan(spinner, "game caches", gameTargets, wl, scope.isAdmin, scope.collapse)...)
+		addItems(clean.ScanSteamUserCaches(wl))
+	}
+
+	// Developer caches: use specialized scanner for safety.
+	var nativeSteps []clean.NativeCleanup
+	if scope.dev {
+		devItems := clean.ScanDevCaches(wl, scope.keepVersions)
+		if own {
+			devItems, nativeSteps = clean.SplitRustup(devItems, clean.DefaultRunner)
+		}
+		if own && native {
+			var pruned []clean.NativeCleanup
+			devItems, pruned = clean.SplitNative(devItems, wl, clean.DefaultRunner)
+			nativeSteps = append(nativeSteps, pruned...)
+		}
+		addItems(devItems)
+	}
+
+	// WER user-level reports (no admin needed).
+	if scope.system {
+		werItems := clean.ScanWERUserReports(wl)
+		if len(werItems) > 0 {
+			results = append(results, clean.ItemsToResult("WER User Reports", werItems))
+		}
+	}
+
+	return results, nativeSteps
+}
+
+// profileWhitelist returns the global whitelist merged with the one saved
+// in profile p, or global alone if that user has none. Other users' files
+// are only read, never created.
+func profileWhitelist(p core.UserProfile, global *whitelist.Whitelist) *whitelist.Whitelist {
+	path := filepath.Join(p.RoamingAppData(), config.AppName, "whitelist.txt")
+	if wl, err := whitelist.LoadExisting(path); err == nil {
+		return whitelist.Merge(global, wl)
+	}
+	return global
+}
+
+// pruneEmptyDirs removes the empty folders left under the cleaned roots.
+// Roots of other profiles (--all-users) are pruned in that profile's
+// environment with its own merged whitelist (see profileWhitelist); the
+// rest use wl.
+func pruneEmptyDirs(
+	results []clean.ScanResult,
+	profiles []core.UserProfile,
+	profileWLs map[string]*whitelist.Whitelist,
+	wl *whitelist.Whitelist,
+) clean.PruneResult {
+	byUser := make(map[string][]clean.ScanResult)
+	for _, r := range results {
+		byUser[r.User] = append(byUser[r.User], r)
+	}
+
+	pruned := clean.PruneEmptyDirs(clean.PruneRoots(byUser[""]), wl)
+	for _, p := range profiles {
+		if len(byUser[p.Name]) == 0 {
+			continue
+		}
+		core.WithProfileEnv(p, func() {
+			res := clean.PruneEmptyDirs(clean.PruneRoots(byUser[p.Name]), profileWLs[p.Name])
+			pruned.Removed = append(pruned.Removed, res.Removed...)
+			pruned.Failed += res.Failed
+		})
+	}
+	return pruned
+}
+
+// streamScan scans config targets through the streaming scanner, showing
+// running totals on the spinner while items arrive. Only per-target
+// totals are kept; the items are streamed again when cleaning. collapse
+// > 0 folds large folders into single items.
+func streamScan(
+	spinner *ui.InlineSpinner,
+	label string,
+	targets []config.CleanTarget,
+	wl *whitelist.Whitelist,
+	isAdmin bool,
+	collapse int,
+) []clean.ScanResult {
+	opts := clean.StreamOptions{CollapseThreshold: collapse}
+
+	var lastUpdate time.Time
+	return clean.SummarizeStream(context.Background(), targets, wl, isAdmin, opts, func(ev clean.ScanEvent) {
+		// Throttle redraws; huge caches emit millions of events.
+		if time.Since(lastUpdate) < 100*time.Millisecond {
+			return
+		}
+		lastUpdate = time.Now()
+		spinner.UpdateMessage(fmt.Sprintf("Scanning %s... %d files, %s",
+			label, ev.RunningFiles, core.FormatSize(ev.RunningSize)))
+	})
+}
+
+// displayTotal prints the total line under the results table.
+func displayTotal(size int64, items int) {
+	fmt.Println(ui.Divider(55))
+	fmt.Printf("  %-35s %s  %s\n",
+		ui.BoldStyle().Render("Total"),
+		ui.FormatSize(size),
+		ui.MutedStyle().Render(fmt.Sprintf("(%d items)", items)),
+	)
+	fmt.Println()
+}
+
+// displayGoalPlan prints the --free plan with its risk breakdown and
+// reports clearly when the goal cannot be reached.
+func displayGoalPlan(plan clean.GoalPlan) {
+	fmt.Println(ui.SectionHeader("Cleanup Plan", 55))
+	fmt.Printf("    %-31s  %10s\n", "Goal", ui.FormatSize(plan.Goal))
+	fmt.Printf("    %-31s  %10s  %s\n",
+		"Selected",
+		ui.FormatSize(plan.Total),
+		ui.MutedStyle().Render(fmt.Sprintf("(%d targets)", len(plan.Selected))),
+	)
+	for _, rt := range plan.RiskBreakdown() {
+		fmt.Printf("      %-29s  %10s  %s\n",
+			rt.RiskLevel+" risk",
+			ui.FormatSize(rt.Size),
+			ui.MutedStyle().Render(fmt.Sprintf("(%d targets)", rt.Count)),
+		)
+	}
+	fmt.Println()
+
+	if !plan.Reachable {
+		fmt.Println(ui.WarningStyle().Render(
+			fmt.Sprintf("  %s  Goal of %s cannot be reached: only %s is cleanable at max risk %q",
+				ui.IconWarning, core.FormatSize(plan.Goal), core.FormatSize(plan.Total), plan.MaxRisk)))
+		if len(plan.Excluded) > 0 {
+			var excluded int64
+			for _, c := range plan.Excluded {
+				excluded += c.Size
+			}
+			fmt.Println(ui.MutedStyle().Render(
+				fmt.Sprintf("     %d targets (%s) are above the risk ceiling; raise --max-risk to include them",
+					len(plan.Excluded), core.FormatSize(excluded))))
+		}
+		fmt.Println()
+	}
+}
+
+// riskBadge renders a compact risk tag: muted for low, orange for medium
+// and red for high.
+func riskBadge(level string) string {
+	switch strings.ToLower(level) {
+	case config.RiskHigh:
+		return ui.TagErrorStyle().Render(" HIGH RISK ")
+	case config.RiskMedium:
+		return ui.TagWarningStyle().Render(" medium ")
+	default:
+		return ui.MutedStyle().Render("low")
+	}
+}
+
+// targetRiskLevel returns the configured risk level of the named target,
+// defaulting to low for unknown targets.
+func targetRiskLevel(targetName string) string {
+	if t, ok := config.GetTarget(targetName); ok {
+		return t.RiskLevel
+	}
+	return config.RiskLow
+}
+
+// itemRisk returns an item's risk level, defaulting to low when unset.
+func itemRisk(item clean.CleanItem) string {
+	if item.RiskLevel == "" {
+		return config.RiskLow
+	}
+	return item.RiskLevel
+}
+
+// withinMaxRisk reports whether the named config target is allowed under
+// the given risk ceiling.
+func withinMaxRisk(targetName, maxRisk string) bool {
+	return config.RiskRank(targetRiskLevel(targetName)) <= config.RiskRank(maxRisk)
+}
+
+// highRiskSummary lists the high-risk targets included in the cleanup,
+// each with its size, for the DangerConfirm prompt.
+func highRiskSummary(results []clean.ScanResult, windowsOldSize int64) []string {
+	var out []string
+	for _, r := range results {
+		if config.RiskRank(r.RiskLevel) >= config.RiskRank(config.RiskHigh) {
+			out = append(out, fmt.Sprintf("%s (%s)", r.Key(), core.FormatSize(r.TotalSize)))
+		}
+	}
+	if windowsOldSize > 0 && config.RiskRank(targetRiskLevel("WindowsOld")) >= config.RiskRank(config.RiskHigh) {
+		out = append(out, fmt.Sprintf("Windows.old (%s)", core.FormatSize(windowsOldSize)))
+	}
+	return out
+}
+
+// cleanSelection is what a clean run will act on: scan results, native
+// prune steps and the API-backed targets, sized.
+type cleanSelection struct {
+	results    []clean.ScanResult
+	native     []clean.NativeCleanup
+	recycleBin int64
+	goMod      int64
+	windowsOld int64
+}
+
+// totals returns the bytes and items the selection would free.
+func (s cleanSelection) totals() (size int64, items int) {
+	size = clean.TotalSizeAll(s.results) + s.recycleBin + s.goMod + s.windowsOld
+	items = clean.TotalItemCount(s.results)
+	for _, nc := range s.native {
+		size += nc.Size
+		items += nc.ItemCount
+	}
+	return size, items
+}
+
+// planGoal plans the --free goal over pool and returns the part of pool
+// the plan selected.
+func planGoal(pool cleanSelection, goal int64, maxRisk string) (cleanSelection, clean.GoalPlan) {
+	candidates := clean.CandidatesFromResults(pool.results)
+	candidates = append(candidates, extraCandidates(pool.recycleBin, pool.goMod, pool.windowsOld)...)
+	for _, nc := range pool.native {
+		candidates = append(candidates, clean.GoalCandidate{
+			Name:      nc.PlanName(),
+			Category:  "dev",
+			Size:      nc.Size,
+			RiskLevel: nc.RiskLevel,
+		})
+	}
+	p := clean.PlanForGoal(candidates, goal, maxRisk)
+
+	sel := pool
+	sel.native = filterNative(pool.native, func(nc clean.NativeCleanup) bool {
+		return p.IsSelected(nc.PlanName())
+	})
+	sel.results = keepPlannedResults(pool.results, p)
+	if !p.IsSelected("RecycleBin") {
+		sel.recycleBin = 0
+	}
+	if !p.IsSelected("GoModCache") {
+		sel.goMod = 0
+	}
+	if !p.IsSelected("WindowsOld") {
+		sel.windowsOld = 0
+	}
+	return sel, p
+}
+
+// extraCandidates expresses the API-backed cleanup steps (Recycle Bin,
+// Go module cache, Windows.old) as goal candidates.
+func extraCandidates(recycleBinSize, goModSize, windowsOldSize int64) []clean.GoalCandidate {
+	extras := []struct {
+		name string
+		size int64
+	}{
+		{"RecycleBin", recycleBinSize},
+		{"GoModCache", goModSize},
+		{"WindowsOld", windowsOldSize},
+	}
+
+	var candidates []clean.GoalCandidate
+	for _, e := range extras {
+		if e.size <= 0 {
+			continue
+		}
+		t, _ := config.GetTarget(e.name)
+		candidates = append(candidates, clean.GoalCandidate{
+			Name:      e.name,
+			Category:  t.Category,
+			Size:      e.size,
+			RiskLevel: t.RiskLevel,
+		})
+	}
+	return candidates
+}
+
+// filterNative returns the native cleanups for which keep returns true.
+func filterNative(steps []clean.NativeCleanup, keep func(clean.NativeCleanup) bool) []clean.NativeCleanup {
+	var kept []clean.NativeCleanup
+	for _, nc := range steps {
+		if keep(nc) {
+			kept = append(kept, nc)
+		}
+	}
+	return kept
+}
+
+// keepPlannedResults drops scan results that the goal plan did not select.
+func keepPlannedResults(results []clean.ScanResult, plan clean.GoalPlan) []clean.ScanResult {
+	kept := make([]clean.ScanResult, 0, len(plan.Selected))
+	for _, r := range results {
+		if plan.IsSelected(r.Key()) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
 }
 
 // groupItemsByDescription groups CleanItems by their Description field.
@@ -0,0 +1,284 @@
+package analyze
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/core"
+)
+
+// ─── Batch Actions ───────────────────────────────────────────────────────────
+// Entries marked in the TUI are acted on together: deleted, moved to
+// another folder or drive, or exported as a list. A plan is built first so
+// the user can preview what will happen; whitelisted entries are set aside
+// in the plan rather than failing halfway through.
+
+// BatchAction is what a batch does with its entries.
+type BatchAction int
+
+const (
+	BatchDelete BatchAction = iota
+	BatchMove
+	BatchExport
+)
+
+func (a BatchAction) String() string {
+	switch a {
+	case BatchMove:
+		return "Move"
+	case BatchExport:
+		return "Export"
+	}
+	return "Delete"
+}
+
+// BatchPlan is the previewed content of a batch.
+type BatchPlan struct {
+	Action  BatchAction
+	Items   []*DirEntry // entries to act on, largest first
+	Skipped []*DirEntry // whitelisted, or holding whitelisted paths
+	Bytes   int64       // total size of Items
+}
+
+// PlanBatch builds a plan for the marked entries. Entries inside another
+// marked directory are dropped, since acting on the directory covers
+// them. For deletes and moves, entries that are whitelisted or contain
+// whitelisted paths are skipped; isWhitelisted may be nil.
+func PlanBatch(action BatchAction, marked []*DirEntry, isWhitelisted func(string) bool) BatchPlan {
+	plan := BatchPlan{Action: action}
+
+	set := make(map[*DirEntry]bool, len(marked))
+	for _, e := range marked {
+		set[e] = true
+	}
+
+	for _, e := range marked {
+		if hasMarkedAncestor(e, set) {
+			continue
+		}
+		if action != BatchExport && isWhitelisted != nil && protected(e, isWhitelisted) {
+			plan.Skipped = append(plan.Skipped, e)
+			continue
+		}
+		plan.Items = append(plan.Items, e)
+		plan.Bytes += e.Size
+	}
+
+	sort.SliceStable(plan.Items, func(i, j int) bool { return plan.Items[i].Size > plan.Items[j].Size })
+	return plan
+}
+
+// hasMarkedAncestor reports whether any parent of e is in set.
+func hasMarkedAncestor(e *DirEntry, set map[*DirEntry]bool) bool {
+	for p := e.Parent; p != nil; p = p.Parent {
+		if set[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// protected reports whether e or anything under it is whitelisted.
+func protected(e *DirEntry, isWhitelisted func(string) bool) bool {
+	if isWhitelisted(e.Path) {
+		return true
+	}
+	for _, c := range e.Children {
+		if protected(c, isWhitelisted) {
+			return true
+		}
+	}
+	return false
+}
+
+// ─── Tree Updates ────────────────────────────────────────────────────────────
+
+// DetachEntry removes e from its parent and subtracts its size from every
+// ancestor, so the tree reflects a delete or move without a rescan.
+func DetachEntry(e *DirEntry) {
+	parent := e.Parent
+	if parent == nil {
+		return
+	}
+	for i, c := range parent.Children {
+		if c == e {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			break
+		}
+	}
+	for p := parent; p != nil; p = p.Parent {
+		p.Size -= e.Size
+	}
+	e.Parent = nil
+}
+
+// AttachEntry adds e, moved on disk into dir, to dir's children with its
+// paths rewritten, and adds its size to dir and every ancestor.
+func AttachEntry(e, dir *DirEntry) {
+	rebasePaths(e, filepath.Join(dir.Path, e.Name))
+	e.Parent = dir
+	dir.Children = append(dir.Children, e)
+	for p := dir; p != nil; p = p.Parent {
+		p.Size += e.Size
+	}
+	sort.SliceStable(dir.Children, func(i, j int) bool { return dir.Children[i].Size > dir.Children[j].Size })
+}
+
+// rebasePaths sets e's path to path and rewrites its descendants to match.
+func rebasePaths(e *DirEntry, path string) {
+	e.Path = path
+	for _, c := range e.Children {
+		rebasePaths(c, filepath.Join(path, c.Name))
+	}
+}
+
+// FindEntry returns the entry for path under root, or nil if path is
+// outside the tree or was not scanned. Names match case-insensitively.
+func FindEntry(root *DirEntry, path string) *DirEntry {
+	rel, err := filepath.Rel(root.Path, filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	e := root
+	if rel == "." {
+		return e
+	}
+next:
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		for _, c := range e.Children {
+			if strings.EqualFold(c.Name, name) {
+				e = c
+				continue next
+			}
+		}
+		return nil
+	}
+	return e
+}
+
+// ─── Move ────────────────────────────────────────────────────────────────────
+
+// MoveEntry moves the file or directory at src into the directory destDir
+// and returns the new path. A rename is tried first; across volumes the
+// data is copied and the source then removed with core.SafeDelete. The
+// destination must not exist, and must not be src or lie inside it.
+func MoveEntry(src, destDir string) (string, error) {
+	if err := core.ValidatePath(src); err != nil {
+		return "", fmt.Errorf("safety check failed for %s: %w", src, err)
+	}
+	if MovesIntoSelf(src, destDir) {
+		return "", fmt.Errorf("cannot move %s into itself", src)
+	}
+	info, err := os.Stat(destDir)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("destination %s is not a folder", destDir)
+	}
+
+	dst := filepath.Join(destDir, filepath.Base(src))
+	if _, err := os.Lstat(dst); err == nil {
+		return "", fmt.Errorf("%s already exists", dst)
+	}
+
+	if err := os.Rename(src, dst); err == nil {
+		return dst, nil
+	}
+
+	// Different volume (or rename refused): copy, then remove the source.
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return "", fmt.Errorf("copy %s: %w", src, err)
+	}
+	if _, err := core.SafeDelete(src, false); err != nil {
+		return dst, fmt.Errorf("copied to %s but could not remove the original: %w", dst, err)
+	}
+	return dst, nil
+}
+
+// MovesIntoSelf reports whether destDir is src or lies inside it. Such a
+// move cannot be renamed, and copying would recurse into the copy and
+// then delete it along with the source.
+func MovesIntoSelf(src, destDir string) bool {
+	src, destDir = filepath.Clean(src), filepath.Clean(destDir)
+	if strings.EqualFold(src, destDir) {
+		return true
+	}
+	prefix := src
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	return len(destDir) > len(prefix) && strings.EqualFold(destDir[:len(prefix)], prefix)
+}
+
+// copyTree copies a file or directory tree, preserving modification times.
+func copyTree(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyFile(src, dst, info)
+	}
+
+	if err := os.Mkdir(dst, info.Mode().Perm()|0o700); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := copyTree(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+func copyFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// ─── Export ──────────────────────────────────────────────────────────────────
+
+// WriteSelection writes entries as CSV (path, size, type, modified).
+func WriteSelection(w io.Writer, entries []*DirEntry) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"path", "size", "type", "modified"})
+	for _, e := range entries {
+		kind := "file"
+		if e.IsDir {
+			kind = "dir"
+		}
+		modified := ""
+		if !e.ModTime.IsZero() {
+			modified = e.ModTime.UTC().Format(time.RFC3339)
+		}
+		_ = cw.Write([]string{e.Path, strconv.FormatInt(e.Size, 10), kind, modified})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,157 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanBatch(t *testing.T) {
+	root := typedTree()
+	videos := findChild(root, "videos")
+	trip := findChild(videos, "a.mp4")
+	isos := findChild(root, "isos")
+	license := findChild(root, "LICENSE")
+
+	// a.mp4 is covered by its marked parent; isos holds a whitelisted file.
+	isWhitelisted := func(p string) bool { return strings.HasSuffix(p, "win.iso") }
+	plan := PlanBatch(BatchDelete, []*DirEntry{trip, license, videos, isos}, isWhitelisted)
+
+	if len(plan.Items) != 2 || plan.Items[0] != videos || plan.Items[1] != license {
+		t.Errorf("items = %v", plan.Items)
+	}
+	if plan.Bytes != videos.Size+license.Size {
+		t.Errorf("bytes = %d, want %d", plan.Bytes, videos.Size+license.Size)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0] != isos {
+		t.Errorf("skipped = %v", plan.Skipped)
+	}
+
+	// Exports list everything; the whitelist only guards changes.
+	if export := PlanBatch(BatchExport, []*DirEntry{isos}, isWhitelisted); len(export.Items) != 1 {
+		t.Errorf("export plan = %+v", export)
+	}
+}
+
+func TestDetachEntry(t *testing.T) {
+	root := typedTree()
+	videos := findChild(root, "videos")
+	trip := findChild(videos, "a.mp4")
+	rootSize, videosSize := root.Size, videos.Size
+
+	DetachEntry(trip)
+
+	if findChild(videos, "a.mp4") != nil || trip.Parent != nil {
+		t.Error("entry still attached")
+	}
+	if videos.Size != videosSize-trip.Size || root.Size != rootSize-trip.Size {
+		t.Errorf("sizes not updated up the chain: videos %d, root %d", videos.Size, root.Size)
+	}
+}
+
+func TestAttachEntry(t *testing.T) {
+	root := typedTree()
+	videos := findChild(root, "videos")
+	isos := findChild(root, "isos")
+	rootSize := root.Size
+
+	if got := FindEntry(root, filepath.Join(root.Path, "ISOS", "Old")); got != findChild(isos, "old") {
+		t.Fatalf("FindEntry = %v", got)
+	}
+	if FindEntry(root, filepath.Join(root.Path, "..", "elsewhere")) != nil {
+		t.Error("FindEntry matched a path outside the tree")
+	}
+
+	DetachEntry(videos)
+	AttachEntry(videos, isos)
+
+	if videos.Parent != isos || findChild(isos, "videos") != videos {
+		t.Fatal("entry not attached under destination")
+	}
+	if want := filepath.Join(isos.Path, "videos", "a.mp4"); findChild(videos, "a.mp4").Path != want {
+		t.Errorf("child path = %s, want %s", findChild(videos, "a.mp4").Path, want)
+	}
+	if root.Size != rootSize || isos.Size != 5000+videos.Size {
+		t.Errorf("sizes after move: root %d (want %d), isos %d", root.Size, rootSize, isos.Size)
+	}
+}
+
+func TestMovesIntoSelf(t *testing.T) {
+	src := filepath.Join("C:", "data", "photos")
+	tests := []struct {
+		dest string
+		want bool
+	}{
+		{src, true},
+		{filepath.Join("C:", "DATA", "Photos", "2024"), true},
+		{src + string(filepath.Separator), true},
+		{filepath.Join("C:", "data", "photos2"), false},
+		{filepath.Join("C:", "data"), false},
+	}
+	for _, tt := range tests {
+		if got := MovesIntoSelf(src, tt.dest); got != tt.want {
+			t.Errorf("MovesIntoSelf(%q, %q) = %v, want %v", src, tt.dest, got, tt.want)
+		}
+	}
+
+	// MoveEntry refuses, leaving the source in place.
+	dir := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MoveEntry(dir, filepath.Join(dir, "sub")); err == nil {
+		t.Error("MoveEntry into its own subfolder succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sub")); err != nil {
+		t.Errorf("source damaged: %v", err)
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	file := filepath.Join(src, "sub", "data.bin")
+	if err := os.WriteFile(file, []byte("payload"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "sub", "data.bin"))
+	if err != nil || string(data) != "payload" {
+		t.Fatalf("copied file = %q, %v", data, err)
+	}
+	if info, _ := os.Stat(filepath.Join(dst, "sub", "data.bin")); !info.ModTime().Equal(mtime) {
+		t.Errorf("mtime not preserved: %v", info.ModTime())
+	}
+	if err := copyTree(src, dst); err == nil {
+		t.Error("copying onto an existing destination should fail")
+	}
+}
+
+func TestWriteSelection(t *testing.T) {
+	root := typedTree()
+	var buf bytes.Buffer
+	if err := WriteSelection(&buf, []*DirEntry{findChild(root, "videos"), findChild(root, "c.mp4")}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1][2] != "dir" || rows[2][1] != "1000" {
+		t.Errorf("rows = %v", rows)
+	}
+}
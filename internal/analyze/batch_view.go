@@ -0,0 +1,409 @@
+package analyze
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakshaymaurya-felt/winmole/internal/core"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+)
+
+// ─── Marks ───────────────────────────────────────────────────────────────────
+// Space marks entries anywhere in the tree; d, m and x open a preview of
+// deleting, moving or exporting them (or the entry under the cursor when
+// nothing is marked). Enter in the preview runs the batch.
+
+// defaultExportName is the suggested file for exporting marked entries.
+const defaultExportName = "winmole-selection.csv"
+
+// toggleMark marks or unmarks e.
+func (m *AnalyzeModel) toggleMark(e *DirEntry) {
+	if m.marked[e] {
+		delete(m.marked, e)
+	} else {
+		m.marked[e] = true
+	}
+}
+
+// markedEntries returns the marked entries, largest first.
+func (m AnalyzeModel) markedEntries() []*DirEntry {
+	out := make([]*DirEntry, 0, len(m.marked))
+	for e := range m.marked {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Size != out[j].Size {
+			return out[i].Size > out[j].Size
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// markedBytes returns the total size of the marked entries, counting
+// entries inside a marked directory once.
+func (m AnalyzeModel) markedBytes() int64 {
+	var total int64
+	for e := range m.marked {
+		if !hasMarkedAncestor(e, m.marked) {
+			total += e.Size
+		}
+	}
+	return total
+}
+
+// updateMarks applies a mark or batch key and reports whether key was one.
+func (m *AnalyzeModel) updateMarks(key string) bool {
+	items := m.visibleItems()
+	switch key {
+	case " ":
+		if m.cursor >= 0 && m.cursor < len(items) {
+			m.toggleMark(items[m.cursor])
+			if !m.treemap && m.cursor < len(items)-1 {
+				m.cursor++
+				m.ensureVisible()
+			}
+		}
+	case "a":
+		// Mark every visible entry, or unmark them if all are marked.
+		all := len(items) > 0
+		for _, e := range items {
+			all = all && m.marked[e]
+		}
+		for _, e := range items {
+			if all {
+				delete(m.marked, e)
+			} else {
+				m.marked[e] = true
+			}
+		}
+	case "u":
+		clear(m.marked)
+	case "d":
+		m.openBatch(BatchDelete)
+	case "m":
+		m.openBatch(BatchMove)
+	case "x":
+		m.openBatch(BatchExport)
+	default:
+		return false
+	}
+	return true
+}
+
+// ─── Batch Preview ───────────────────────────────────────────────────────────
+
+// batchState is an open batch preview.
+type batchState struct {
+	plan    BatchPlan
+	input   string // destination folder or export file
+	running bool
+}
+
+// batchResultMsg reports a finished batch.
+type batchResultMsg struct {
+	plan  BatchPlan
+	done  []*DirEntry
+	bytes int64
+	dest  string
+	errs  []error
+}
+
+// openBatch previews action on the marked entries, or on the entry under
+// the cursor when nothing is marked.
+func (m *AnalyzeModel) openBatch(action BatchAction) {
+	if m.readOnly && action != BatchExport {
+		return
+	}
+	if m.live != nil {
+		// Sizes are provisional and the scanner is still attaching entries.
+		m.err = errors.New("scan still running; delete, move and export are available once it finishes")
+		return
+	}
+	entries := m.markedEntries()
+	if len(entries) == 0 {
+		items := m.visibleItems()
+		if m.cursor < 0 || m.cursor >= len(items) {
+			return
+		}
+		entries = []*DirEntry{items[m.cursor]}
+	}
+
+	b := &batchState{plan: PlanBatch(action, entries, m.isWhitelisted)}
+	if action == BatchExport {
+		b.input = defaultExportName
+	}
+	m.batch = b
+	m.status = ""
+}
+
+// updateBatch handles keys while a batch preview is open.
+func (m AnalyzeModel) updateBatch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := m.batch
+	if b.running {
+		return m, nil
+	}
+	editable := b.plan.Action != BatchDelete
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.batch = nil
+
+	case tea.KeyEnter:
+		if len(b.plan.Items) == 0 {
+			m.batch = nil
+			return m, nil
+		}
+		dest := strings.TrimSpace(b.input)
+		if editable && dest == "" {
+			return m, nil
+		}
+		if b.plan.Action == BatchMove {
+			for _, e := range b.plan.Items {
+				if MovesIntoSelf(e.Path, dest) {
+					m.err = fmt.Errorf("cannot move %s into itself", e.Path)
+					return m, nil
+				}
+			}
+		}
+		m.err = nil
+		b.running = true
+		return m, runBatch(b.plan, dest, m.dryRun, m.isWhitelisted)
+
+	case tea.KeyBackspace:
+		if r := []rune(b.input); editable && len(r) > 0 {
+			b.input = string(r[:len(r)-1])
+		}
+
+	case tea.KeyRunes, tea.KeySpace:
+		if editable {
+			b.input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// runBatch carries out plan in the background.
+func runBatch(plan BatchPlan, dest string, dryRun bool, isWhitelisted func(string) bool) tea.Cmd {
+	return func() tea.Msg {
+		res := batchResultMsg{plan: plan, dest: dest}
+
+		// The whitelist may have changed since the preview was built.
+		guarded := func(e *DirEntry) bool {
+			if isWhitelisted != nil && protected(e, isWhitelisted) {
+				res.errs = append(res.errs, fmt.Errorf("%s is whitelisted", e.Path))
+				return true
+			}
+			return false
+		}
+
+		switch plan.Action {
+		case BatchDelete:
+			for _, e := range plan.Items {
+				if guarded(e) {
+					continue
+				}
+				freed, err := core.SafeDeleteWithWhitelist(e.Path, dryRun, isWhitelisted)
+				if err != nil {
+					res.errs = append(res.errs, err)
+					continue
+				}
+				res.done = append(res.done, e)
+				res.bytes += freed
+			}
+
+		case BatchMove:
+			for _, e := range plan.Items {
+				if guarded(e) {
+					continue
+				}
+				if !dryRun {
+					if _, err := MoveEntry(e.Path, dest); err != nil {
+						res.errs = append(res.errs, err)
+						continue
+					}
+				}
+				res.done = append(res.done, e)
+				res.bytes += e.Size
+			}
+
+		case BatchExport:
+			f, err := os.Create(dest)
+			if err == nil {
+				err = WriteSelection(f, plan.Items)
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+			}
+			if err != nil {
+				res.errs = append(res.errs, err)
+			} else {
+				res.done = plan.Items
+				res.bytes = plan.Bytes
+			}
+		}
+		return res
+	}
+}
+
+// applyBatch updates the tree and status after a batch finished.
+func (m *AnalyzeModel) applyBatch(res batchResultMsg) {
+	m.batch = nil
+	changed := res.plan.Action != BatchExport && !m.dryRun
+
+	// Moves into a folder inside the scanned tree reappear there.
+	var dest *DirEntry
+	if changed && res.plan.Action == BatchMove {
+		dest = FindEntry(m.root, res.dest)
+	}
+
+	for _, e := range res.done {
+		if !changed {
+			continue
+		}
+		// Leave a directory that is going away for its nearest survivor.
+		if m.current == e || hasAncestor(m.current, e) {
+			m.showDir(e.Parent)
+		}
+		DetachEntry(e)
+		// Marks inside a removed directory go with it.
+		for marked := range m.marked {
+			if marked == e || hasAncestor(marked, e) {
+				delete(m.marked, marked)
+			}
+		}
+		if dest != nil && dest.IsDir {
+			AttachEntry(e, dest)
+		}
+	}
+	if changed && len(res.done) > 0 {
+		m.types.breakdown.Root = nil
+		if m.tab == tabTypes {
+			m.showTypes()
+		}
+		if items := m.visibleItems(); m.cursor >= len(items) {
+			m.cursor = max(len(items)-1, 0)
+		}
+		m.ensureVisible()
+	}
+
+	n, size := len(res.done), ui.FormatSize(res.bytes)
+	switch {
+	case res.plan.Action == BatchExport && n > 0:
+		m.status = fmt.Sprintf("Exported %d entries to %s", n, res.dest)
+	case m.dryRun && res.plan.Action == BatchDelete:
+		m.status = fmt.Sprintf("[DRY RUN] Would delete %d items (%s)", n, size)
+	case m.dryRun && res.plan.Action == BatchMove:
+		m.status = fmt.Sprintf("[DRY RUN] Would move %d items (%s) to %s", n, size, res.dest)
+	case res.plan.Action == BatchDelete:
+		m.status = fmt.Sprintf("Deleted %d items, freed %s", n, size)
+	case res.plan.Action == BatchMove:
+		m.status = fmt.Sprintf("Moved %d items (%s) to %s", n, size, res.dest)
+	}
+
+	m.err = nil
+	if len(res.errs) == 1 {
+		m.err = res.errs[0]
+	} else if len(res.errs) > 1 {
+		m.err = fmt.Errorf("%d items failed; first: %w", len(res.errs), res.errs[0])
+	}
+}
+
+// showDir makes dir the current directory, rebuilding the breadcrumb from
+// the root.
+func (m *AnalyzeModel) showDir(dir *DirEntry) {
+	var chain []*DirEntry
+	for p := dir.Parent; p != nil; p = p.Parent {
+		chain = append([]*DirEntry{p}, chain...)
+	}
+	m.breadcrumb = chain
+	m.current = dir
+	m.cursor, m.offset = 0, 0
+}
+
+// hasAncestor reports whether dir is a parent of e at any level.
+func hasAncestor(e, dir *DirEntry) bool {
+	for p := e.Parent; p != nil; p = p.Parent {
+		if p == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// ─── Batch Rendering ─────────────────────────────────────────────────────────
+
+func (m AnalyzeModel) renderBatch(w int) string {
+	b := m.batch
+	p := b.plan
+
+	verb := p.Action.String()
+	titleColor := ui.ColorCoral
+	if p.Action == BatchDelete {
+		titleColor = ui.ColorError
+	}
+	title := fmt.Sprintf("  %s %d items · %s", verb, len(p.Items), ui.FormatSize(p.Bytes))
+	if m.dryRun && p.Action != BatchExport {
+		title += "  [DRY RUN]"
+	}
+	lines := []string{lipgloss.NewStyle().Foreground(titleColor).Bold(true).Render(title)}
+
+	switch p.Action {
+	case BatchMove:
+		lines = append(lines, "  Move to folder: "+b.input+
+			lipgloss.NewStyle().Foreground(clrCursor).Render("▏"))
+	case BatchExport:
+		lines = append(lines, "  Export list to: "+b.input+
+			lipgloss.NewStyle().Foreground(clrCursor).Render("▏"))
+	}
+	if b.running {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorMuted).Italic(true).Render("  Working…"))
+	}
+	lines = append(lines, "")
+
+	vh := m.viewportHeight() - len(lines)
+	row := func(e *DirEntry, note string, color lipgloss.AdaptiveColor) string {
+		line := fmt.Sprintf("  %10s  %s", ui.FormatSize(e.Size), lipgloss.NewStyle().Foreground(color).Render(e.Path))
+		if note != "" {
+			line += lipgloss.NewStyle().Foreground(ui.ColorMuted).Italic(true).Render("  " + note)
+		}
+		return line
+	}
+	shown := 0
+	for _, e := range p.Items {
+		if shown == vh-1 {
+			break
+		}
+		lines = append(lines, row(e, "", kindColor(e)))
+		shown++
+	}
+	for _, e := range p.Skipped {
+		if shown == vh-1 {
+			break
+		}
+		lines = append(lines, row(e, "skipped: whitelisted", ui.ColorMuted))
+		shown++
+	}
+	if rest := len(p.Items) + len(p.Skipped) - shown; rest > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorMuted).Italic(true).
+			Render(fmt.Sprintf("  … and %d more", rest)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markTag renders the running total of marked entries for the footer.
+func (m AnalyzeModel) markTag() string {
+	return lipgloss.NewStyle().
+		Foreground(ui.ColorSurfaceDark).
+		Background(ui.ColorSuccess).
+		Render(fmt.Sprintf(" %d marked · %s ", len(m.marked), ui.FormatSize(m.markedBytes())))
+}
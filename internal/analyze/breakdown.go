@@ -0,0 +1,158 @@
+package analyze
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// ─── Type Breakdown ──────────────────────────────────────────────────────────
+// Bytes and file counts aggregated by extension and by coarse kind, for the
+// subtree under any entry. Aggregation walks the scanned tree, so it needs
+// no extra disk access and works on cached and imported trees alike.
+
+// TypeStat is the total for one extension or kind.
+type TypeStat struct {
+	Key   string   // extension (".mp4", "" for none) or kind name
+	Kind  FileKind // kind of the extension, or the kind itself
+	Bytes int64
+	Count int
+}
+
+// Breakdown holds the per-extension and per-kind totals for a subtree,
+// each sorted by bytes, largest first.
+type Breakdown struct {
+	Root   *DirEntry
+	ByKind []TypeStat
+	ByExt  []TypeStat
+	Bytes  int64
+	Files  int
+}
+
+// BreakdownOf aggregates the files under root.
+func BreakdownOf(root *DirEntry) Breakdown {
+	b := Breakdown{Root: root}
+	if root == nil {
+		return b
+	}
+
+	exts := make(map[string]*TypeStat)
+	kinds := make(map[FileKind]*TypeStat)
+
+	walkFiles(root, func(f *DirEntry) {
+		ext := Extension(f.Name)
+		kind := KindOf(f.Name)
+
+		es, ok := exts[ext]
+		if !ok {
+			es = &TypeStat{Key: ext, Kind: kind}
+			exts[ext] = es
+		}
+		es.Bytes += f.Size
+		es.Count++
+
+		ks, ok := kinds[kind]
+		if !ok {
+			ks = &TypeStat{Key: string(kind), Kind: kind}
+			kinds[kind] = ks
+		}
+		ks.Bytes += f.Size
+		ks.Count++
+
+		b.Bytes += f.Size
+		b.Files++
+	})
+
+	for _, s := range exts {
+		b.ByExt = append(b.ByExt, *s)
+	}
+	for _, s := range kinds {
+		b.ByKind = append(b.ByKind, *s)
+	}
+	sortStats(b.ByExt)
+	sortStats(b.ByKind)
+	return b
+}
+
+// sortStats orders by bytes descending, then key, for stable output.
+func sortStats(stats []TypeStat) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Bytes != stats[j].Bytes {
+			return stats[i].Bytes > stats[j].Bytes
+		}
+		return stats[i].Key < stats[j].Key
+	})
+}
+
+// walkFiles calls fn for every file (not directory) under e.
+func walkFiles(e *DirEntry, fn func(*DirEntry)) {
+	if !e.IsDir {
+		fn(e)
+		return
+	}
+	for _, c := range e.Children {
+		walkFiles(c, fn)
+	}
+}
+
+// ─── Largest Files ───────────────────────────────────────────────────────────
+
+// LargestFiles returns up to n files under root for which match returns
+// true (all files if match is nil), largest first.
+func LargestFiles(root *DirEntry, match func(*DirEntry) bool, n int) []*DirEntry {
+	if root == nil || n <= 0 {
+		return nil
+	}
+
+	// Keep the n largest in a min-heap so huge trees are not sorted whole.
+	h := &entryHeap{}
+	walkFiles(root, func(f *DirEntry) {
+		if match != nil && !match(f) {
+			return
+		}
+		h.offer(f, n)
+	})
+	return h.sorted()
+}
+
+// MatchStat returns a LargestFiles filter for files counted in s, where s
+// comes from ByKind (byExt false) or ByExt (byExt true).
+func MatchStat(s TypeStat, byExt bool) func(*DirEntry) bool {
+	if byExt {
+		return func(f *DirEntry) bool { return Extension(f.Name) == s.Key }
+	}
+	return func(f *DirEntry) bool { return KindOf(f.Name) == s.Kind }
+}
+
+// entryHeap is a min-heap of entries by size.
+type entryHeap []*DirEntry
+
+func (h entryHeap) Len() int           { return len(h) }
+func (h entryHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h entryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *entryHeap) Push(x any)        { *h = append(*h, x.(*DirEntry)) }
+func (h *entryHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// offer adds e if the heap holds fewer than n entries or e is larger than
+// the smallest one, which it then replaces.
+func (h *entryHeap) offer(e *DirEntry, n int) {
+	if h.Len() < n {
+		heap.Push(h, e)
+	} else if n > 0 && e.Size > (*h)[0].Size {
+		(*h)[0] = e
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted empties the heap, returning its entries largest first.
+func (h *entryHeap) sorted() []*DirEntry {
+	out := make([]*DirEntry, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(*DirEntry)
+	}
+	return out
+}
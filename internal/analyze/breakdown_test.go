@@ -0,0 +1,84 @@
+package analyze
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// typedTree builds a small tree with a mix of file types.
+func typedTree() *DirEntry {
+	root := &DirEntry{Path: filepath.Join("C:", "media"), Name: "media", IsDir: true}
+	add := func(parent *DirEntry, name string, size int64) *DirEntry {
+		e := &DirEntry{Path: filepath.Join(parent.Path, name), Name: name, Size: size, Parent: parent, IsDir: size < 0}
+		if e.IsDir {
+			e.Size = 0
+		}
+		parent.Children = append(parent.Children, e)
+		return e
+	}
+	videos := add(root, "videos", -1)
+	add(videos, "a.mp4", 4000)
+	add(videos, "b.MKV", 3000)
+	add(videos, "notes.txt", 10)
+	isos := add(root, "isos", -1)
+	add(isos, "win.iso", 5000)
+	add(isos, "old", -1)
+	add(root, "c.mp4", 1000)
+	add(root, "LICENSE", 5)
+	calculateSizes(root)
+	return root
+}
+
+func TestBreakdownOf(t *testing.T) {
+	b := BreakdownOf(typedTree())
+
+	if b.Files != 6 || b.Bytes != 13015 {
+		t.Errorf("totals = %d files %d bytes, want 6 files 13015 bytes", b.Files, b.Bytes)
+	}
+
+	want := []TypeStat{
+		{Key: "video", Kind: KindVideo, Bytes: 8000, Count: 3},
+		{Key: "disk image", Kind: KindDiskImage, Bytes: 5000, Count: 1},
+		{Key: "document", Kind: KindDocument, Bytes: 10, Count: 1},
+		{Key: "other", Kind: KindOther, Bytes: 5, Count: 1},
+	}
+	if len(b.ByKind) != len(want) {
+		t.Fatalf("ByKind = %+v", b.ByKind)
+	}
+	for i := range want {
+		if b.ByKind[i] != want[i] {
+			t.Errorf("ByKind[%d] = %+v, want %+v", i, b.ByKind[i], want[i])
+		}
+	}
+
+	if b.ByExt[0].Key != ".iso" || b.ByExt[1] != (TypeStat{Key: ".mp4", Kind: KindVideo, Bytes: 5000, Count: 2}) {
+		t.Errorf("ByExt = %+v", b.ByExt)
+	}
+	if last := b.ByExt[len(b.ByExt)-1]; last.Key != "" || last.Count != 1 {
+		t.Errorf("files without extension should group under \"\", got %+v", last)
+	}
+
+	// Any subtree can be broken down.
+	if sub := BreakdownOf(findChild(b.Root, "videos")); sub.Files != 3 || len(sub.ByKind) != 2 {
+		t.Errorf("subtree breakdown = %+v", sub)
+	}
+}
+
+func TestLargestFiles(t *testing.T) {
+	root := typedTree()
+
+	videos := LargestFiles(root, MatchStat(TypeStat{Kind: KindVideo}, false), 2)
+	if len(videos) != 2 || videos[0].Name != "a.mp4" || videos[1].Name != "b.MKV" {
+		t.Errorf("largest videos = %v", videos)
+	}
+
+	mp4 := LargestFiles(root, MatchStat(TypeStat{Key: ".mp4"}, true), 10)
+	if len(mp4) != 2 || mp4[0].Name != "a.mp4" || mp4[1].Name != "c.mp4" {
+		t.Errorf("largest .mp4 = %v", mp4)
+	}
+
+	all := LargestFiles(root, nil, 100)
+	if len(all) != 6 || all[0].Name != "win.iso" || all[5].Name != "LICENSE" {
+		t.Errorf("all files = %v", all)
+	}
+}
@@ -1,7 +1,6 @@
 package analyze
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,8 +8,19 @@ import (
 )
 
 const (
-	cacheFileName = "analyze_cache.json"
-	cacheTTL      = 5 * time.Minute
+	cacheFileName = "analyze_cache.bin"
+
+	// legacyCacheFileName is the JSON cache written by older versions.
+	legacyCacheFileName = "analyze_cache.json"
+
+	// cacheMaxAge bounds how long a cached tree is revalidated
+	// incrementally. In-place file growth does not change directory
+	// mtimes, so older caches are dropped for a full rescan.
+	cacheMaxAge = 7 * 24 * time.Hour
+
+	// cacheCompress gzips the cache body, trading a little CPU for a
+	// several times smaller file on large trees.
+	cacheCompress = true
 )
 
 // cacheEntry wraps a scan result with metadata for validation.
@@ -35,7 +45,7 @@ func cacheDir() (string, error) {
 }
 
 // cachePath generates a cache file path keyed by the scan root.
-func cachePath(rootPath string) string {
+func cachePath(rootPath, fileName string) string {
 	dir, err := cacheDir()
 	if err != nil {
 		return ""
@@ -45,14 +55,16 @@ func cachePath(rootPath string) string {
 	if len(safe) > 80 {
 		safe = safe[:80]
 	}
-	return filepath.Join(dir, safe+"_"+cacheFileName)
+	return filepath.Join(dir, safe+"_"+fileName)
 }
 
-// SaveCache persists scan results to disk. Non-sensitive: only paths, sizes,
-// and timestamps are stored.
+// SaveCache persists scan results to disk in the binary cache format
+// (see codec.go). Non-sensitive: only paths, sizes, and timestamps are
+// stored. The file is replaced atomically and any legacy JSON cache for
+// the same root is removed.
 func SaveCache(root *DirEntry, rootPath string) error {
-	path := cachePath(rootPath)
-	if path == "" {
+	path := cachePath(rootPath, cacheFileName)
+	if path == "" || root == nil {
 		return nil
 	}
 
@@ -61,46 +73,72 @@ func SaveCache(root *DirEntry, rootPath string) error {
 		RootPath:  rootPath,
 		Root:      root,
 	}
+	if err := writeCacheFile(path, entry); err != nil {
+		return err
+	}
+
+	_ = os.Remove(cachePath(rootPath, legacyCacheFileName))
+	return nil
+}
 
-	data, err := json.Marshal(entry)
+// writeCacheFile encodes entry to path, replacing it atomically.
+func writeCacheFile(path string, entry cacheEntry) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
 	if err != nil {
 		return err
 	}
-
-	return os.WriteFile(path, data, 0o644)
+	if err := encodeCache(f, entry, cacheCompress); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
 }
 
-// LoadCache loads cached scan results if they exist and haven't expired.
+// LoadCache loads cached scan results for rootPath if they exist and are
+// younger than cacheMaxAge. The tree may be stale; pass it to
+// Scanner.ScanIncremental to revalidate it against the disk. Caches in the
+// legacy JSON format are read too.
 // Returns os.ErrNotExist if no valid cache is found.
 func LoadCache(rootPath string) (*DirEntry, error) {
-	path := cachePath(rootPath)
+	path := cachePath(rootPath, cacheFileName)
 	if path == "" {
 		return nil, os.ErrNotExist
 	}
 
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		f, err = os.Open(cachePath(rootPath, legacyCacheFileName))
+	}
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
 
-	var entry cacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
+	entry, err := decodeCache(f)
+	if err != nil {
 		return nil, err
 	}
 
 	// Validate: root path must match.
-	if entry.RootPath != rootPath {
+	if entry.RootPath != rootPath || entry.Root == nil {
 		return nil, os.ErrNotExist
 	}
 
-	// Validate: cache must not be expired.
-	if time.Since(entry.Timestamp) > cacheTTL {
+	// Validate: cache must not be too old to revalidate.
+	if time.Since(entry.Timestamp) > cacheMaxAge {
 		return nil, os.ErrNotExist
 	}
 
-	// Rebuild parent pointers (not serialized to avoid circular refs).
-	rebuildParents(entry.Root, nil)
-
 	return entry.Root, nil
 }
 
@@ -0,0 +1,235 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// syntheticTree builds a tree of dirs×dirs directories with files files
+// each, using the repetitive names typical of real drives.
+func syntheticTree(dirs, files int) *DirEntry {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	root := &DirEntry{Path: filepath.Join("C:", "data"), Name: "data", IsDir: true, Scanned: true, ModTime: base}
+	for i := 0; i < dirs; i++ {
+		d := &DirEntry{Name: fmt.Sprintf("project-%d", i), IsDir: true, Scanned: true, Parent: root,
+			ModTime: base.Add(time.Duration(i) * time.Second)}
+		d.Path = filepath.Join(root.Path, d.Name)
+		for j := 0; j < dirs; j++ {
+			sub := &DirEntry{Name: "node_modules", IsDir: true, Scanned: true, Parent: d, ModTime: base}
+			if j > 0 {
+				sub.Name = fmt.Sprintf("pkg-%d", j)
+			}
+			sub.Path = filepath.Join(d.Path, sub.Name)
+			for k := 0; k < files; k++ {
+				f := &DirEntry{Name: fmt.Sprintf("index-%d.js", k%20), Size: int64(k*37 + 1), Scanned: true,
+					Parent: sub, ModTime: base.Add(-time.Duration(k) * time.Minute)}
+				f.Path = filepath.Join(sub.Path, f.Name)
+				sub.Children = append(sub.Children, f)
+				sub.Size += f.Size
+			}
+			d.Children = append(d.Children, sub)
+			d.Size += sub.Size
+		}
+		root.Children = append(root.Children, d)
+		root.Size += d.Size
+	}
+	return root
+}
+
+// assertSameTree compares two trees field by field.
+func assertSameTree(t *testing.T, want, got *DirEntry, parent *DirEntry) {
+	t.Helper()
+	if got.Path != want.Path || got.Name != want.Name || got.Size != want.Size ||
+		got.IsDir != want.IsDir || got.Scanned != want.Scanned || !got.ModTime.Equal(want.ModTime) {
+		t.Fatalf("node mismatch:\n got %+v\nwant %+v", got, want)
+	}
+	if got.Parent != parent {
+		t.Fatalf("%s: parent pointer not restored", got.Path)
+	}
+	if len(got.Children) != len(want.Children) {
+		t.Fatalf("%s: %d children, want %d", got.Path, len(got.Children), len(want.Children))
+	}
+	for i := range want.Children {
+		assertSameTree(t, want.Children[i], got.Children[i], got)
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	tree := syntheticTree(4, 30)
+	tree.Children[0].Children[0].Children[0].ModTime = time.Time{} // zero mtime
+
+	for _, compress := range []bool{false, true} {
+		entry := cacheEntry{Timestamp: time.Now(), RootPath: `C:\data`, Root: tree}
+
+		var buf bytes.Buffer
+		if err := encodeCache(&buf, entry, compress); err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeCache(&buf)
+		if err != nil {
+			t.Fatalf("compress=%v: %v", compress, err)
+		}
+		if got.RootPath != entry.RootPath || !got.Timestamp.Equal(entry.Timestamp) {
+			t.Errorf("compress=%v: header = %q %v", compress, got.RootPath, got.Timestamp)
+		}
+		assertSameTree(t, tree, got.Root, nil)
+	}
+}
+
+func TestCodec_ReadsLegacyJSON(t *testing.T) {
+	tree := syntheticTree(2, 5)
+	data, err := json.Marshal(cacheEntry{Timestamp: time.Now(), RootPath: `C:\data`, Root: tree})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decodeCache(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameTree(t, tree, got.Root, nil)
+}
+
+func TestCodec_RejectsUnknownVersionAndCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	if err := encodeCache(&buf, cacheEntry{Timestamp: time.Now(), Root: syntheticTree(2, 5)}, false); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	future := append([]byte(nil), data...)
+	future[len(cacheMagic)] = cacheVersion + 1
+	if _, err := decodeCache(bytes.NewReader(future)); !errors.Is(err, errCacheVersion) {
+		t.Errorf("future version: err = %v, want errCacheVersion", err)
+	}
+
+	if _, err := decodeCache(bytes.NewReader(data[:len(data)/2])); !errors.Is(err, errCacheCorrupt) {
+		t.Errorf("truncated: err = %v, want errCacheCorrupt", err)
+	}
+}
+
+func TestCodec_SmallerThanJSON(t *testing.T) {
+	entry := cacheEntry{Timestamp: time.Now(), RootPath: `C:\data`, Root: syntheticTree(10, 50)}
+
+	js, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bin bytes.Buffer
+	if err := encodeCache(&bin, entry, false); err != nil {
+		t.Fatal(err)
+	}
+	if bin.Len()*4 > len(js) {
+		t.Errorf("binary cache is %d bytes, JSON %d; want at least 4x smaller", bin.Len(), len(js))
+	}
+}
+
+func TestSaveLoadCache_LegacyFallback(t *testing.T) {
+	t.Setenv("APPDATA", t.TempDir())
+	tree := syntheticTree(2, 3)
+	rootPath := `C:\data`
+
+	// An old JSON cache is still picked up.
+	data, err := json.Marshal(cacheEntry{Timestamp: time.Now(), RootPath: rootPath, Root: tree})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := cachePath(rootPath, legacyCacheFileName)
+	if err := os.WriteFile(legacy, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadCache(rootPath); err != nil || got.Size != tree.Size {
+		t.Fatalf("LoadCache(legacy) = %v, %v", got, err)
+	}
+
+	// Saving writes the binary format and retires the JSON file.
+	if err := SaveCache(tree, rootPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy cache should be removed after SaveCache, stat err = %v", err)
+	}
+	got, err := LoadCache(rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameTree(t, tree, got, nil)
+}
+
+// ─── Benchmarks ──────────────────────────────────────────────────────────────
+// go test -bench Cache -benchmem ./internal/analyze compares the binary
+// format with the JSON format it replaced.
+
+func benchmarkEntry() cacheEntry {
+	return cacheEntry{Timestamp: time.Now(), RootPath: `C:\data`, Root: syntheticTree(30, 100)} // ~90k nodes
+}
+
+func BenchmarkCacheSave(b *testing.B) {
+	entry := benchmarkEntry()
+
+	b.Run("json", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(len(data)), "bytes/file")
+		}
+	})
+	for _, compress := range []bool{false, true} {
+		name := "binary"
+		if compress {
+			name = "binary-gzip"
+		}
+		b.Run(name, func(b *testing.B) {
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := encodeCache(&buf, entry, compress); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(buf.Len()), "bytes/file")
+		})
+	}
+}
+
+func BenchmarkCacheLoad(b *testing.B) {
+	entry := benchmarkEntry()
+
+	js, err := json.Marshal(entry)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("json", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeCache(bytes.NewReader(js)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := encodeCache(&buf, entry, compress); err != nil {
+			b.Fatal(err)
+		}
+		name := "binary"
+		if compress {
+			name = "binary-gzip"
+		}
+		data := buf.Bytes()
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeCache(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package analyze
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+// ─── Binary Cache Format ─────────────────────────────────────────────────────
+// A JSON cache of a full drive repeats the full path on every node and runs
+// to hundreds of MB. The binary format instead writes the tree depth-first
+// with names relative to the parent, interns repeated names, stores
+// varints, and derives directory sizes and paths on load:
+//
+//	header  "WMAC" | version u8 | flags u8
+//	body    (gzip if flagGzip) timestamp | cache key | root path | root node
+//	node    kind u8 | name | [size] | mtime delta | [child count | children…]
+//	name    uvarint 0 + length + bytes (new) or uvarint i+1 (i-th seen name)
+//
+// Files written before the binary format (plain JSON) are still readable.
+
+const (
+	cacheMagic   = "WMAC"
+	cacheVersion = 1
+
+	// cacheFlagGzip marks a gzip-compressed body.
+	cacheFlagGzip = 1 << 0
+)
+
+// Node kind bits.
+const (
+	nodeDir     = 1 << 0
+	nodeScanned = 1 << 1
+	nodeNoTime  = 1 << 2 // ModTime is the zero time.
+)
+
+// maxCacheName bounds name lengths accepted from a cache file.
+const maxCacheName = 1 << 15
+
+var (
+	errCacheVersion = errors.New("unsupported analyze cache version")
+	errCacheCorrupt = errors.New("corrupt analyze cache")
+)
+
+// ─── Encoding ────────────────────────────────────────────────────────────────
+
+// encodeCache writes entry, which must have a Root, to w in the binary
+// format, gzip-compressing the body if compress is set.
+func encodeCache(w io.Writer, entry cacheEntry, compress bool) error {
+	bw := bufio.NewWriterSize(w, 64<<10)
+
+	var flags byte
+	if compress {
+		flags |= cacheFlagGzip
+	}
+	bw.WriteString(cacheMagic)
+	bw.WriteByte(cacheVersion)
+	bw.WriteByte(flags)
+
+	var body io.Writer = bw
+	var zw *gzip.Writer
+	if compress {
+		zw, _ = gzip.NewWriterLevel(bw, gzip.BestSpeed)
+		body = zw
+	}
+
+	enc := &treeEncoder{w: body, names: make(map[string]uint64)}
+	enc.varint(entry.Timestamp.UnixNano())
+	enc.literal(entry.RootPath)
+	enc.literal(entry.Root.Path)
+	enc.node(entry.Root, 0)
+	enc.flush()
+	if enc.err != nil {
+		return enc.err
+	}
+
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// treeEncoder writes nodes, remembering the first error.
+type treeEncoder struct {
+	w     io.Writer
+	buf   []byte
+	names map[string]uint64
+	err   error
+}
+
+func (e *treeEncoder) flush() {
+	if e.err == nil {
+		_, e.err = e.w.Write(e.buf)
+	}
+	e.buf = e.buf[:0]
+}
+
+func (e *treeEncoder) uvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *treeEncoder) varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+// literal writes a length-prefixed string.
+func (e *treeEncoder) literal(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// name writes s as a back-reference if it was seen before.
+func (e *treeEncoder) name(s string) {
+	if i, ok := e.names[s]; ok {
+		e.uvarint(i + 1)
+		return
+	}
+	e.names[s] = uint64(len(e.names))
+	e.uvarint(0)
+	e.literal(s)
+}
+
+// node writes n and its subtree. parentTime is the parent's mtime in Unix
+// nanoseconds; mtimes are stored as deltas to keep them short.
+func (e *treeEncoder) node(n *DirEntry, parentTime int64) {
+	var kind byte
+	if n.IsDir {
+		kind |= nodeDir
+	}
+	if n.Scanned {
+		kind |= nodeScanned
+	}
+	mtime := parentTime
+	if n.ModTime.IsZero() {
+		kind |= nodeNoTime
+	} else {
+		mtime = n.ModTime.UnixNano()
+	}
+
+	e.buf = append(e.buf, kind)
+	e.name(n.Name)
+	if !n.IsDir {
+		e.uvarint(uint64(max(n.Size, 0)))
+	}
+	if kind&nodeNoTime == 0 {
+		e.varint(mtime - parentTime)
+	}
+	if n.IsDir {
+		e.uvarint(uint64(len(n.Children)))
+	}
+	if len(e.buf) >= 32<<10 {
+		e.flush()
+	}
+
+	for _, c := range n.Children {
+		e.node(c, mtime)
+	}
+}
+
+// ─── Decoding ────────────────────────────────────────────────────────────────
+
+// decodeCache reads a cache file in either the binary or the legacy JSON
+// format.
+func decodeCache(r io.Reader) (cacheEntry, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+
+	head, err := br.Peek(len(cacheMagic))
+	if err != nil {
+		return cacheEntry{}, errCacheCorrupt
+	}
+	if !bytes.Equal(head, []byte(cacheMagic)) {
+		return decodeJSONCache(br)
+	}
+	_, _ = br.Discard(len(cacheMagic))
+
+	version, err := br.ReadByte()
+	if err != nil {
+		return cacheEntry{}, errCacheCorrupt
+	}
+	if version != cacheVersion {
+		return cacheEntry{}, fmt.Errorf("%w: %d", errCacheVersion, version)
+	}
+	flags, err := br.ReadByte()
+	if err != nil {
+		return cacheEntry{}, errCacheCorrupt
+	}
+
+	body := br
+	if flags&cacheFlagGzip != 0 {
+		zr, zErr := gzip.NewReader(br)
+		if zErr != nil {
+			return cacheEntry{}, errCacheCorrupt
+		}
+		defer zr.Close()
+		body = bufio.NewReaderSize(zr, 64<<10)
+	}
+
+	dec := &treeDecoder{r: body}
+	entry := cacheEntry{
+		Timestamp: time.Unix(0, dec.varint()),
+		RootPath:  dec.literal(),
+	}
+	rootPath := dec.literal()
+	root := dec.node(nil, rootPath, 0)
+	if dec.err != nil {
+		return cacheEntry{}, errCacheCorrupt
+	}
+	entry.Root = root
+	return entry, nil
+}
+
+// decodeJSONCache reads the legacy JSON cache format.
+func decodeJSONCache(r io.Reader) (cacheEntry, error) {
+	var entry cacheEntry
+	if err := json.NewDecoder(r).Decode(&entry); err != nil {
+		return cacheEntry{}, err
+	}
+	// Rebuild parent pointers (not serialized to avoid circular refs).
+	rebuildParents(entry.Root, nil)
+	return entry, nil
+}
+
+// treeDecoder reads nodes, remembering the first error.
+type treeDecoder struct {
+	r     *bufio.Reader
+	names []string
+	err   error
+}
+
+func (d *treeDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *treeDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *treeDecoder) literal() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > maxCacheName {
+		d.err = errCacheCorrupt
+		return ""
+	}
+	b := make([]byte, n)
+	_, d.err = io.ReadFull(d.r, b)
+	return string(b)
+}
+
+func (d *treeDecoder) name() string {
+	ref := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if ref == 0 {
+		s := d.literal()
+		d.names = append(d.names, s)
+		return s
+	}
+	if ref > uint64(len(d.names)) {
+		d.err = errCacheCorrupt
+		return ""
+	}
+	return d.names[ref-1]
+}
+
+// node reads a node and its subtree. path is the node's full path; for
+// children it is derived from the parent.
+func (d *treeDecoder) node(parent *DirEntry, path string, parentTime int64) *DirEntry {
+	kind, err := d.r.ReadByte()
+	if err != nil {
+		d.err = err
+		return nil
+	}
+
+	n := &DirEntry{
+		Name:    d.name(),
+		IsDir:   kind&nodeDir != 0,
+		Scanned: kind&nodeScanned != 0,
+		Parent:  parent,
+	}
+	if parent != nil {
+		path = filepath.Join(parent.Path, n.Name)
+	}
+	n.Path = path
+
+	if !n.IsDir {
+		n.Size = int64(d.uvarint())
+	}
+	mtime := parentTime
+	if kind&nodeNoTime == 0 {
+		mtime = parentTime + d.varint()
+		n.ModTime = time.Unix(0, mtime)
+	}
+	if !n.IsDir {
+		return n
+	}
+
+	count := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	n.Children = make([]*DirEntry, 0, min(count, 1024))
+	for i := uint64(0); i < count; i++ {
+		c := d.node(n, "", mtime)
+		if d.err != nil {
+			return nil
+		}
+		n.Children = append(n.Children, c)
+		n.Size += c.Size
+	}
+	return n
+}
@@ -0,0 +1,132 @@
+package analyze
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ─── Tree Diff ───────────────────────────────────────────────────────────────
+// Two trees of the same root (typically snapshots taken days apart) are
+// compared directory by directory. Directories present in both are listed
+// when their size changed and descended into; directories present in only
+// one are listed as added or removed as a whole.
+
+// ChangeKind classifies a directory in a diff.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeGrown   ChangeKind = "grown"
+	ChangeShrunk  ChangeKind = "shrunk"
+)
+
+// DirChange is one changed directory. Path is relative to the roots.
+type DirChange struct {
+	Path   string     `json:"path"`
+	Change ChangeKind `json:"change"`
+	Before int64      `json:"before"`
+	After  int64      `json:"after"`
+	Delta  int64      `json:"delta"`
+}
+
+// TreeDiff is the result of DiffTrees.
+type TreeDiff struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	FromTime time.Time   `json:"from_time,omitzero"`
+	ToTime   time.Time   `json:"to_time,omitzero"`
+	Before   int64       `json:"before"`
+	After    int64       `json:"after"`
+	Delta    int64       `json:"delta"`
+	Changes  []DirChange `json:"changes"`
+}
+
+// DiffTrees compares the directories of a (older) and b (newer). Changes
+// are sorted by the size of the byte delta, largest first. Roots are
+// matched regardless of their paths, so a tree can be compared with an
+// export of the same folder taken on another machine.
+func DiffTrees(a, b *DirEntry) TreeDiff {
+	d := TreeDiff{
+		From:    a.Path,
+		To:      b.Path,
+		Before:  a.Size,
+		After:   b.Size,
+		Delta:   b.Size - a.Size,
+		Changes: []DirChange{},
+	}
+	diffDirs(a, b, "", &d.Changes)
+
+	sort.SliceStable(d.Changes, func(i, j int) bool {
+		di, dj := abs64(d.Changes[i].Delta), abs64(d.Changes[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		return d.Changes[i].Path < d.Changes[j].Path
+	})
+	return d
+}
+
+// diffDirs appends the changes between the subdirectories of a and b.
+func diffDirs(a, b *DirEntry, rel string, out *[]DirChange) {
+	before := subdirs(a)
+	for _, nb := range b.Children {
+		if !nb.IsDir {
+			continue
+		}
+		path := filepath.Join(rel, nb.Name)
+		na, ok := before[nb.Name]
+		if !ok {
+			if nb.Size > 0 {
+				*out = append(*out, DirChange{Path: path, Change: ChangeAdded, After: nb.Size, Delta: nb.Size})
+			}
+			continue
+		}
+		delete(before, nb.Name)
+
+		if na.Size != nb.Size {
+			change := ChangeGrown
+			if nb.Size < na.Size {
+				change = ChangeShrunk
+			}
+			*out = append(*out, DirChange{Path: path, Change: change,
+				Before: na.Size, After: nb.Size, Delta: nb.Size - na.Size})
+			diffDirs(na, nb, path, out)
+		}
+	}
+
+	for name, na := range before {
+		if na.Size > 0 {
+			*out = append(*out, DirChange{Path: filepath.Join(rel, name), Change: ChangeRemoved,
+				Before: na.Size, Delta: -na.Size})
+		}
+	}
+}
+
+// subdirs indexes the subdirectories of e by name.
+func subdirs(e *DirEntry) map[string]*DirEntry {
+	m := make(map[string]*DirEntry)
+	for _, c := range e.Children {
+		if c.IsDir {
+			m[c.Name] = c
+		}
+	}
+	return m
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// WriteJSON writes d as indented JSON.
+func (d TreeDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
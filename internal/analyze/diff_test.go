@@ -0,0 +1,94 @@
+package analyze
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffTrees(t *testing.T) {
+	before := typedTree()
+	after := typedTree()
+	after.Path = filepath.Join("D:", "media") // roots match regardless of path
+
+	// videos grows, isos disappears, a new downloads folder appears.
+	videos := findChild(after, "videos")
+	videos.Children = append(videos.Children, &DirEntry{Name: "new.mp4", Size: 20000, Parent: videos})
+	after.Children = []*DirEntry{videos, findChild(after, "c.mp4")}
+	after.Children = append(after.Children, &DirEntry{Name: "downloads", IsDir: true, Parent: after,
+		Children: []*DirEntry{{Name: "x.zip", Size: 700}}})
+	calculateSizes(after)
+
+	d := DiffTrees(before, after)
+	if d.Delta != after.Size-before.Size {
+		t.Errorf("net delta = %d, want %d", d.Delta, after.Size-before.Size)
+	}
+
+	want := []DirChange{
+		{Path: "videos", Change: ChangeGrown, Before: 7010, After: 27010, Delta: 20000},
+		{Path: "isos", Change: ChangeRemoved, Before: 5000, Delta: -5000},
+		{Path: "downloads", Change: ChangeAdded, After: 700, Delta: 700},
+	}
+	if len(d.Changes) != len(want) {
+		t.Fatalf("changes = %+v", d.Changes)
+	}
+	for i := range want {
+		if d.Changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, d.Changes[i], want[i])
+		}
+	}
+}
+
+func TestDiffTrees_Nested(t *testing.T) {
+	before := syntheticTree(2, 3)
+	after := syntheticTree(2, 3)
+	leaf := after.Children[1].Children[0]
+	leaf.Children[0].Size += 1000
+	calculateSizes(after)
+
+	d := DiffTrees(before, after)
+	if len(d.Changes) != 2 {
+		t.Fatalf("changes = %+v", d.Changes)
+	}
+	if d.Changes[0].Path != "project-1" || d.Changes[1].Path != filepath.Join("project-1", "node_modules") {
+		t.Errorf("growth should be reported down the chain, got %+v", d.Changes)
+	}
+
+	if same := DiffTrees(before, syntheticTree(2, 3)); len(same.Changes) != 0 {
+		t.Errorf("identical trees: %+v", same.Changes)
+	}
+}
+
+func TestSnapshots_SaveListLoad(t *testing.T) {
+	t.Setenv("APPDATA", t.TempDir())
+	tree := syntheticTree(2, 3)
+
+	name, err := SaveSnapshot(tree, "")
+	if err != nil || name == "" {
+		t.Fatalf("SaveSnapshot(unnamed) = %q, %v", name, err)
+	}
+	if _, err := SaveSnapshot(tree, "before-update"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveSnapshot(tree, `..\escape`); err == nil {
+		t.Error("snapshot names with separators should be rejected")
+	}
+
+	snaps, err := ListSnapshots()
+	if err != nil || len(snaps) != 2 {
+		t.Fatalf("ListSnapshots = %+v, %v", snaps, err)
+	}
+
+	got, taken, err := LoadSnapshot("before-update")
+	if err != nil || taken.IsZero() {
+		t.Fatalf("LoadSnapshot = %v, %v, %v", got, taken, err)
+	}
+	assertSameTree(t, tree, got, nil)
+
+	// A file path works too.
+	if got, _, err := LoadSnapshot(snaps[0].Path); err != nil || got.Size != tree.Size {
+		t.Errorf("LoadSnapshot(path) = %v, %v", got, err)
+	}
+	if _, _, err := LoadSnapshot("missing"); err == nil {
+		t.Error("LoadSnapshot(missing) should fail")
+	}
+}
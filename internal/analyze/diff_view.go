@@ -0,0 +1,161 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+)
+
+// ─── Diff Model ──────────────────────────────────────────────────────────────
+
+// DiffModel is the bubbletea Model listing the changes of a TreeDiff.
+type DiffModel struct {
+	diff     TreeDiff
+	cursor   int
+	offset   int
+	width    int
+	height   int
+	quitting bool
+}
+
+// NewDiffModel creates a DiffModel for d.
+func NewDiffModel(d TreeDiff) DiffModel {
+	return DiffModel{diff: d, width: 80, height: 24}
+}
+
+func (m DiffModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		n := len(m.diff.Changes)
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = max(min(m.cursor+1, n-1), 0)
+		case "pgup":
+			m.cursor = max(m.cursor-m.viewportHeight(), 0)
+		case "pgdown":
+			m.cursor = max(min(m.cursor+m.viewportHeight(), n-1), 0)
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = max(n-1, 0)
+		}
+	}
+	m.offset = scrollOffset(m.cursor, m.offset, m.viewportHeight())
+	return m, nil
+}
+
+func (m DiffModel) viewportHeight() int {
+	return max(m.height-9, 1) // header (5) + footer (2) + padding
+}
+
+// ─── Diff Rendering ──────────────────────────────────────────────────────────
+
+func (m DiffModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	w := max(m.width, 40)
+	d := m.diff
+
+	// Header: the two sides and the net change.
+	title := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorCoral).
+		Render("  " + ui.IconDiamond + " What Changed")
+	side := func(label, path string, size int64, at string) string {
+		return lipgloss.NewStyle().Foreground(ui.ColorTextDim).
+			Render(fmt.Sprintf("  %-5s %s  %s  %s", label, path, ui.FormatSize(size), at))
+	}
+	stamp := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+	net := "  Net " + deltaStyle(d.Delta).Render(formatDelta(d.Delta)) +
+		lipgloss.NewStyle().Foreground(ui.ColorMuted).
+			Render(fmt.Sprintf("  across %d changed folders", len(d.Changes)))
+	header := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorCoral).
+		Width(w - 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title,
+			side("From", d.From, d.Before, stamp(d.FromTime)),
+			side("To", d.To, d.After, stamp(d.ToTime)),
+			net))
+
+	// Body: one row per changed folder, bars scaled to the largest delta.
+	var lines []string
+	if len(d.Changes) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorMuted).Italic(true).
+			Render("  (no folder changed size)"))
+	}
+	var largest int64 = 1
+	if len(d.Changes) > 0 {
+		largest = max(abs64(d.Changes[0].Delta), 1)
+	}
+	vh := m.viewportHeight()
+	for i := m.offset; i < len(d.Changes) && i < m.offset+vh; i++ {
+		c := d.Changes[i]
+		pct := float64(abs64(c.Delta)) / float64(largest) * 100
+		line := fmt.Sprintf("  %s %s  %s  %s  %s",
+			ui.GradientBar(pct, 16),
+			deltaStyle(c.Delta).Render(fmt.Sprintf("%11s", formatDelta(c.Delta))),
+			changeTag(c.Change),
+			lipgloss.NewStyle().Foreground(ui.ColorMuted).
+				Render(fmt.Sprintf("%9s → %-9s", ui.FormatSize(c.Before), ui.FormatSize(c.After))),
+			c.Path)
+		lines = append(lines, withCursor(line, i == m.cursor))
+	}
+
+	footer := ui.HintBarStyle().Render("  " + strings.Join(
+		[]string{"↑↓ nav", "PgUp/PgDn page", "q quit"}, " "+ui.IconPipe+" "))
+
+	return header + "\n" + strings.Join(lines, "\n") + "\n" + footer
+}
+
+// formatDelta formats a byte delta with an explicit sign.
+func formatDelta(n int64) string {
+	if n < 0 {
+		return "-" + ui.FormatSize(-n)
+	}
+	return "+" + ui.FormatSize(n)
+}
+
+// deltaStyle colours growth as a warning and shrinkage as success.
+func deltaStyle(n int64) lipgloss.Style {
+	switch {
+	case n > 0:
+		return lipgloss.NewStyle().Foreground(ui.ColorError).Bold(true)
+	case n < 0:
+		return lipgloss.NewStyle().Foreground(ui.ColorSuccess).Bold(true)
+	}
+	return lipgloss.NewStyle().Foreground(ui.ColorMuted)
+}
+
+// changeTag renders a fixed-width label for a change kind.
+func changeTag(c ChangeKind) string {
+	color := ui.ColorMuted
+	switch c {
+	case ChangeAdded, ChangeGrown:
+		color = ui.ColorError
+	case ChangeRemoved, ChangeShrunk:
+		color = ui.ColorSuccess
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%-7s", c))
+}
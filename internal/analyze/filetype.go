@@ -0,0 +1,66 @@
+package analyze
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ─── File Types ──────────────────────────────────────────────────────────────
+
+// FileKind is a coarse file type derived from the extension.
+type FileKind string
+
+const (
+	KindVideo     FileKind = "video"
+	KindAudio     FileKind = "audio"
+	KindImage     FileKind = "image"
+	KindArchive   FileKind = "archive"
+	KindDiskImage FileKind = "disk image"
+	KindInstaller FileKind = "installer"
+	KindCode      FileKind = "code"
+	KindDocument  FileKind = "document"
+	KindOther     FileKind = "other"
+)
+
+// extKinds maps lower-case extensions (with the dot) to their kind.
+var extKinds = map[string]FileKind{}
+
+// init fills extKinds from an ordered list and panics on an extension
+// listed under two kinds, so KindOf never depends on map order. .ts is
+// code: TypeScript sources far outnumber MPEG transport streams.
+func init() {
+	for _, k := range []struct {
+		kind FileKind
+		exts []string
+	}{
+		{KindVideo, []string{".mp4", ".mkv", ".avi", ".mov", ".wmv", ".webm", ".m4v", ".flv", ".mpg", ".mpeg", ".m2ts"}},
+		{KindAudio, []string{".mp3", ".flac", ".wav", ".aac", ".ogg", ".m4a", ".wma", ".opus"}},
+		{KindImage, []string{".jpg", ".jpeg", ".png", ".gif", ".bmp", ".tif", ".tiff", ".webp", ".heic", ".raw", ".cr2", ".nef", ".psd", ".svg"}},
+		{KindArchive, []string{".zip", ".7z", ".rar", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".zst", ".cab", ".lz4"}},
+		{KindDiskImage, []string{".iso", ".img", ".vhd", ".vhdx", ".vmdk", ".vdi", ".qcow2", ".wim", ".esd", ".dmg"}},
+		{KindInstaller, []string{".exe", ".msi", ".msix", ".msixbundle", ".appx", ".appxbundle", ".msp", ".nupkg"}},
+		{KindCode, []string{".go", ".js", ".ts", ".tsx", ".jsx", ".py", ".java", ".cs", ".c", ".cpp", ".h", ".rs", ".rb", ".php", ".json", ".xml", ".yaml", ".yml", ".toml", ".sql", ".sh", ".ps1", ".html", ".css", ".dll", ".pdb", ".lib", ".obj", ".class", ".jar", ".wasm"}},
+		{KindDocument, []string{".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".odt", ".ods", ".txt", ".md", ".rtf", ".csv", ".epub", ".pst", ".ost"}},
+	} {
+		for _, ext := range k.exts {
+			if prev, dup := extKinds[ext]; dup {
+				panic("analyze: extension " + ext + " listed as both " + string(prev) + " and " + string(k.kind))
+			}
+			extKinds[ext] = k.kind
+		}
+	}
+}
+
+// Extension returns the lower-case extension of name including the dot,
+// or "" if it has none.
+func Extension(name string) string {
+	return strings.ToLower(filepath.Ext(name))
+}
+
+// KindOf returns the coarse type of a file by its name.
+func KindOf(name string) FileKind {
+	if kind, ok := extKinds[Extension(name)]; ok {
+		return kind
+	}
+	return KindOther
+}
@@ -0,0 +1,22 @@
+package analyze
+
+import "testing"
+
+func TestKindOf(t *testing.T) {
+	for name, want := range map[string]FileKind{
+		"Holiday.MP4":      KindVideo,
+		"setup.msi":        KindInstaller,
+		"win11.iso":        KindDiskImage,
+		"backup.tar.gz":    KindArchive,
+		"report.pdf":       KindDocument,
+		"main.go":          KindCode,
+		"index.ts":         KindCode,
+		"README":           KindOther,
+		"pagefile.sys":     KindOther,
+		"Outlook Data.pst": KindDocument,
+	} {
+		if got := KindOf(name); got != want {
+			t.Errorf("KindOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
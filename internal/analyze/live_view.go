@@ -0,0 +1,102 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+)
+
+// ─── Live Scan ───────────────────────────────────────────────────────────────
+// The browser can open on a tree that ScanLive is still filling. The view
+// is redrawn on a timer, directories still being read are marked, and
+// actions that change the tree wait until the scan has finished.
+
+// liveTick is how often a live tree is redrawn.
+const liveTick = 200 * time.Millisecond
+
+// liveScan is a scan still running in the background.
+type liveScan struct {
+	scanner *Scanner
+	done    <-chan struct{}
+	frame   int
+}
+
+type (
+	liveTickMsg struct{}
+	liveDoneMsg struct{}
+)
+
+// WithLiveScan returns a copy of m browsing a tree that s is still filling
+// (see Scanner.ScanLive); done is closed when the scan finishes.
+func (m AnalyzeModel) WithLiveScan(s *Scanner, done <-chan struct{}) AnalyzeModel {
+	m.live = &liveScan{scanner: s, done: done}
+	return m
+}
+
+// cmds redraws on a timer and waits for the scan to finish.
+func (l *liveScan) cmds() tea.Cmd {
+	return tea.Batch(liveTickCmd(), func() tea.Msg {
+		<-l.done
+		return liveDoneMsg{}
+	})
+}
+
+func liveTickCmd() tea.Cmd {
+	return tea.Tick(liveTick, func(time.Time) tea.Msg { return liveTickMsg{} })
+}
+
+// finishLive switches to the finished tree, returning a search to rerun
+// if one is open.
+func (m *AnalyzeModel) finishLive() tea.Cmd {
+	m.status = fmt.Sprintf("Scan complete: %d entries", m.live.scanner.ScannedCount())
+	m.live = nil
+	m.err = nil
+	// Breakdowns and search results taken from the partial tree are stale.
+	m.types.breakdown.Root = nil
+	if m.tab == tabTypes {
+		m.showTypes()
+	}
+	if items := m.visibleItems(); m.cursor >= len(items) {
+		m.cursor = max(len(items)-1, 0)
+	}
+	m.ensureVisible()
+	if m.search.active && m.search.query != "" {
+		m.search.seq++
+		m.search.busy = true
+		return m.runSearch()
+	}
+	return nil
+}
+
+// sortedLive returns items ordered by their current size. The scanner
+// only sorts a directory once it is complete, so one still being read
+// is sorted for display.
+func sortedLive(items []*DirEntry) []*DirEntry {
+	out := append([]*DirEntry(nil), items...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Size > out[j].Size })
+	return out
+}
+
+// pending reports whether e is a directory the live scan is still reading.
+func (m AnalyzeModel) pending(e *DirEntry) bool {
+	return m.live != nil && e.IsDir && !e.Scanned
+}
+
+// ─── Live Rendering ──────────────────────────────────────────────────────────
+
+// scanningLine renders the progress line shown in the header.
+func (m AnalyzeModel) scanningLine() string {
+	frame := ui.SpinnerFrames[m.live.frame%len(ui.SpinnerFrames)]
+	return lipgloss.NewStyle().Foreground(ui.ColorPrimary).Render("  "+frame) +
+		lipgloss.NewStyle().Foreground(ui.ColorMuted).Italic(true).
+			Render(fmt.Sprintf(" Scanning… %d entries · sizes are provisional", m.live.scanner.ScannedCount()))
+}
+
+// pendingTag marks a directory that is still being read.
+func pendingTag() string {
+	return lipgloss.NewStyle().Foreground(ui.ColorMuted).Render(ui.IconPending)
+}
@@ -4,26 +4,11 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/lakshaymaurya-felt/winmole/internal/core"
 )
 
-// ─── Messages ────────────────────────────────────────────────────────────────
-
-type deleteResultMsg struct {
-	path  string
-	freed int64
-	err   error
-}
-
-func deleteEntry(entry *DirEntry) tea.Cmd {
-	return func() tea.Msg {
-		freed, err := core.SafeDelete(entry.Path, false)
-		return deleteResultMsg{path: entry.Path, freed: freed, err: err}
-	}
-}
-
 // ─── Model ───────────────────────────────────────────────────────────────────
 
 // AnalyzeModel is the bubbletea Model for the disk analyzer TUI.
@@ -34,9 +19,20 @@ type AnalyzeModel struct {
 	breadcrumb    []*DirEntry // navigation history stack
 	width         int
 	height        int
-	offset        int  // viewport scroll offset
-	largeOnly     bool // filter: show only >100MB
-	confirmDelete bool // two-key delete: Backspace then Enter
+	offset        int                // viewport scroll offset
+	filter        ViewFilter         // narrows the current view
+	filterAge     time.Duration      // age preset behind filter.OlderThan
+	search        searchState        // "/" search prompt and results
+	marked        map[*DirEntry]bool // entries selected for a batch action
+	batch         *batchState        // open batch preview, or nil
+	isWhitelisted func(string) bool  // protects paths from delete and move
+	dryRun        bool               // batches only report what they would do
+	status        string             // outcome of the last batch
+	readOnly      bool               // imported tree: no delete or open
+	treemap       bool               // show the treemap instead of the list
+	live          *liveScan          // scan still filling the tree, or nil
+	tab           analyzeTab
+	types         typesState
 	quitting      bool
 	err           error
 }
@@ -46,16 +42,47 @@ func NewAnalyzeModel(root *DirEntry) AnalyzeModel {
 	return AnalyzeModel{
 		root:    root,
 		current: root,
+		marked:  make(map[*DirEntry]bool),
 		width:   80,
 		height:  24,
 	}
 }
 
+// WithWhitelist returns a copy of m whose deletes and moves skip paths
+// for which isWhitelisted returns true.
+func (m AnalyzeModel) WithWhitelist(isWhitelisted func(string) bool) AnalyzeModel {
+	m.isWhitelisted = isWhitelisted
+	return m
+}
+
+// WithDryRun returns a copy of m whose deletes and moves only report what
+// they would do.
+func (m AnalyzeModel) WithDryRun(dryRun bool) AnalyzeModel {
+	m.dryRun = dryRun
+	return m
+}
+
+// WithReadOnly returns a copy of m that cannot delete or open entries, for
+// trees imported from another machine whose paths must not be touched here.
+func (m AnalyzeModel) WithReadOnly() AnalyzeModel {
+	m.readOnly = true
+	return m
+}
+
 func (m AnalyzeModel) Init() tea.Cmd {
+	if m.live != nil {
+		return m.live.cmds()
+	}
 	return nil
 }
 
 func (m AnalyzeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Hold off the scanner while a live tree is read.
+	if l := m.live; l != nil {
+		l.scanner.RLock()
+		defer l.scanner.RUnlock()
+	}
+
 	switch msg := msg.(type) {
 
 	case tea.WindowSizeMsg:
@@ -64,18 +91,26 @@ func (m AnalyzeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		// If awaiting delete confirmation, only Enter confirms.
-		if m.confirmDelete {
-			if msg.String() == "enter" {
-				m.confirmDelete = false
-				items := m.visibleItems()
-				if m.cursor >= 0 && m.cursor < len(items) {
-					return m, deleteEntry(items[m.cursor])
-				}
-			}
-			m.confirmDelete = false
+		if m.batch != nil {
+			return m.updateBatch(msg)
+		}
+		if m.search.active {
+			return m.updateSearch(msg)
+		}
+		if msg.String() == "/" {
+			m.search = searchState{active: true}
 			return m, nil
 		}
+		if m.tab == tabTypes {
+			return m.updateTypes(msg)
+		}
+		if msg.String() == "tab" {
+			m.showTypes()
+			return m, nil
+		}
+		if m.treemap {
+			return m.updateTreemap(msg)
+		}
 
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
@@ -96,56 +131,48 @@ func (m AnalyzeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "right", "l":
-			// Drill into a directory.
-			items := m.visibleItems()
-			if m.cursor >= 0 && m.cursor < len(items) {
-				entry := items[m.cursor]
-				if entry.IsDir && len(entry.Children) > 0 {
-					m.breadcrumb = append(m.breadcrumb, m.current)
-					m.current = entry
-					m.cursor = 0
-					m.offset = 0
-				}
-			}
+			m.drillInto()
 
 		case "enter":
 			// Open file/folder location in Explorer.
 			items := m.visibleItems()
-			if m.cursor >= 0 && m.cursor < len(items) {
+			if !m.readOnly && m.cursor >= 0 && m.cursor < len(items) {
 				openInExplorer(items[m.cursor].Path)
 			}
 
 		case "left", "h":
-			// Go up to parent directory.
-			if len(m.breadcrumb) > 0 {
-				m.current = m.breadcrumb[len(m.breadcrumb)-1]
-				m.breadcrumb = m.breadcrumb[:len(m.breadcrumb)-1]
-				m.cursor = 0
-				m.offset = 0
-			}
+			m.goBack()
 
 		case "backspace":
-			// First key of two-key delete confirmation.
-			items := m.visibleItems()
-			if m.cursor >= 0 && m.cursor < len(items) {
-				m.confirmDelete = true
-			}
+			m.openBatch(BatchDelete)
+
+		case "t":
+			m.treemap = true
 
-		case "L":
-			m.largeOnly = !m.largeOnly
-			m.cursor = 0
-			m.offset = 0
+		default:
+			if !m.updateMarks(msg.String()) {
+				m.updateFilter(msg.String())
+			}
 		}
 
 		return m, nil
 
-	case deleteResultMsg:
-		if msg.err != nil {
-			m.err = msg.err
-		} else {
-			m.removeEntry(msg.path)
-		}
+	case batchResultMsg:
+		m.applyBatch(msg)
 		return m, nil
+
+	case liveTickMsg:
+		if m.live == nil {
+			return m, nil
+		}
+		m.live.frame++
+		return m, liveTickCmd()
+
+	case liveDoneMsg:
+		return m, m.finishLive()
+
+	case searchDueMsg, searchResultMsg:
+		return m.updateSearchMsg(msg)
 	}
 
 	return m, nil
@@ -153,71 +180,86 @@ func (m AnalyzeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View delegates to view.go renderView.
 func (m AnalyzeModel) View() string {
+	if l := m.live; l != nil {
+		l.scanner.RLock()
+		defer l.scanner.RUnlock()
+	}
 	return m.renderView()
 }
 
 // ─── Helpers ─────────────────────────────────────────────────────────────────
 
+// drillInto descends into the selected directory.
+func (m *AnalyzeModel) drillInto() {
+	items := m.visibleItems()
+	if m.cursor < 0 || m.cursor >= len(items) {
+		return
+	}
+	entry := items[m.cursor]
+	if entry.IsDir && (len(entry.Children) > 0 || m.pending(entry)) {
+		m.breadcrumb = append(m.breadcrumb, m.current)
+		m.current = entry
+		m.cursor = 0
+		m.offset = 0
+	}
+}
+
+// goBack returns to the parent directory.
+func (m *AnalyzeModel) goBack() {
+	if len(m.breadcrumb) > 0 {
+		m.current = m.breadcrumb[len(m.breadcrumb)-1]
+		m.breadcrumb = m.breadcrumb[:len(m.breadcrumb)-1]
+		m.cursor = 0
+		m.offset = 0
+	}
+}
+
 func (m *AnalyzeModel) ensureVisible() {
-	vh := m.viewportHeight()
-	if m.cursor < m.offset {
-		m.offset = m.cursor
+	m.offset = scrollOffset(m.cursor, m.offset, m.viewportHeight())
+}
+
+// scrollOffset returns the viewport offset that keeps cursor within a
+// viewport of vh rows, moving the current offset as little as possible.
+func scrollOffset(cursor, offset, vh int) int {
+	if cursor < offset {
+		offset = cursor
 	}
-	if m.cursor >= m.offset+vh {
-		m.offset = m.cursor - vh + 1
+	if cursor >= offset+vh {
+		offset = cursor - vh + 1
 	}
+	return offset
 }
 
 func (m *AnalyzeModel) viewportHeight() int {
-	h := m.height - 8 // header (4) + footer (3) + padding
+	h := m.height - 9 // header (5) + footer (3) + padding
 	if h < 1 {
 		h = 1
 	}
 	return h
 }
 
-// visibleItems returns the children of the current directory, optionally
-// filtered to only entries ≥100 MiB.
+// visibleItems returns the children of the current directory that pass
+// the view filter.
 func (m AnalyzeModel) visibleItems() []*DirEntry {
 	if m.current == nil {
 		return nil
 	}
-	if !m.largeOnly {
-		return m.current.Children
+	items := m.current.Children
+	if m.pending(m.current) {
+		items = sortedLive(items)
+	}
+	if !m.filter.Active() {
+		return items
 	}
-	const threshold int64 = 100 * 1024 * 1024 // 100 MiB
 	var out []*DirEntry
-	for _, c := range m.current.Children {
-		if c.Size >= threshold {
+	for _, c := range items {
+		if m.filter.Match(c) {
 			out = append(out, c)
 		}
 	}
 	return out
 }
 
-// removeEntry deletes an entry from the current Children slice and
-// recalculates the parent size.
-func (m *AnalyzeModel) removeEntry(path string) {
-	if m.current == nil {
-		return
-	}
-	for i, c := range m.current.Children {
-		if c.Path == path {
-			m.current.Children = append(m.current.Children[:i], m.current.Children[i+1:]...)
-			// Recalculate current directory size.
-			var total int64
-			for _, child := range m.current.Children {
-				total += child.Size
-			}
-			m.current.Size = total
-			if m.cursor >= len(m.current.Children) && m.cursor > 0 {
-				m.cursor--
-			}
-			return
-		}
-	}
-}
-
 // openInExplorer opens the parent folder of a path with the item selected.
 func openInExplorer(path string) {
 	if runtime.GOOS == "windows" {
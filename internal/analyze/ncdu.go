@@ -0,0 +1,303 @@
+package analyze
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ─── ncdu Export / Import ────────────────────────────────────────────────────
+// Scans are exchanged in the ncdu JSON export format (version 1.2), so a
+// tree scanned on one machine can be browsed elsewhere, and trees exported
+// by ncdu itself can be viewed in WinMole:
+//
+//	[1, 2, {metadata}, [{root info}, {file}, [{dir info}, …], …]]
+//
+// A directory is an array whose first element describes it; files are
+// objects. Only name, asize, dsize and mtime are used.
+
+const (
+	ncduMajor = 1
+	ncduMinor = 2
+)
+
+var errNcduFormat = errors.New("not an ncdu export")
+
+// ncduMeta is the metadata object written after the version numbers.
+type ncduMeta struct {
+	Progname  string `json:"progname"`
+	Progver   string `json:"progver"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ncduInfo is the per-entry object. Sizes are written as both apparent
+// (asize) and disk usage (dsize) since allocation sizes are not scanned.
+type ncduInfo struct {
+	Name  string `json:"name"`
+	Asize int64  `json:"asize,omitempty"`
+	Dsize int64  `json:"dsize,omitempty"`
+	Mtime int64  `json:"mtime,omitempty"`
+}
+
+// ─── Export ──────────────────────────────────────────────────────────────────
+
+// ExportNcdu writes the tree under root to w in the ncdu JSON export
+// format. version is recorded as the exporting program version.
+func ExportNcdu(w io.Writer, root *DirEntry, version string) error {
+	bw := bufio.NewWriterSize(w, 64<<10)
+
+	meta, err := json.Marshal(ncduMeta{
+		Progname:  "winmole",
+		Progver:   version,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, "[%d,%d,%s,\n", ncduMajor, ncduMinor, meta)
+
+	// The root is named by its full path, as ncdu does.
+	if err := writeNcduEntry(bw, root, root.Path); err != nil {
+		return err
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// writeNcduEntry writes e, named name, and its subtree.
+func writeNcduEntry(w *bufio.Writer, e *DirEntry, name string) error {
+	info := ncduInfo{Name: name}
+	if !e.ModTime.IsZero() {
+		info.Mtime = e.ModTime.Unix()
+	}
+	if !e.IsDir {
+		info.Asize = e.Size
+		info.Dsize = e.Size
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	if !e.IsDir {
+		_, err = w.Write(data)
+		return err
+	}
+
+	w.WriteByte('[')
+	w.Write(data)
+	for _, c := range e.Children {
+		w.WriteString(",\n")
+		if err := writeNcduEntry(w, c, c.Name); err != nil {
+			return err
+		}
+	}
+	_, err = w.WriteString("]")
+	return err
+}
+
+// ─── Import ──────────────────────────────────────────────────────────────────
+
+// ImportNcdu reads an ncdu JSON export (from ncdu or ExportNcdu) into a
+// tree. The input is decoded token by token, so large exports are not
+// held in memory twice.
+func ImportNcdu(r io.Reader) (*DirEntry, error) {
+	dec := json.NewDecoder(bufio.NewReaderSize(r, 64<<10))
+	dec.UseNumber()
+
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, errNcduFormat
+	}
+	var major, minor json.Number
+	if err := dec.Decode(&major); err != nil {
+		return nil, errNcduFormat
+	}
+	if err := dec.Decode(&minor); err != nil {
+		return nil, errNcduFormat
+	}
+	if major.String() != fmt.Sprint(ncduMajor) {
+		return nil, fmt.Errorf("unsupported ncdu export version %s.%s", major, minor)
+	}
+
+	var meta json.RawMessage
+	if err := dec.Decode(&meta); err != nil {
+		return nil, errNcduFormat
+	}
+
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, errNcduFormat
+	}
+	root, err := readNcduDir(dec, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	calculateSizes(root)
+	return root, nil
+}
+
+// readNcduDir reads a directory array whose opening bracket has been
+// consumed.
+func readNcduDir(dec *json.Decoder, parent *DirEntry) (*DirEntry, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, fmt.Errorf("ncdu export: directory without info object")
+	}
+	dir, err := readNcduInfo(dec, parent)
+	if err != nil {
+		return nil, err
+	}
+	dir.IsDir = true
+	dir.Size = 0
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("ncdu export: %w", err)
+		}
+		var child *DirEntry
+		switch tok {
+		case json.Delim('['):
+			child, err = readNcduDir(dec, dir)
+		case json.Delim('{'):
+			child, err = readNcduInfo(dec, dir)
+		default:
+			err = fmt.Errorf("ncdu export: unexpected %v in %s", tok, dir.Path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		dir.Children = append(dir.Children, child)
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return nil, fmt.Errorf("ncdu export: unterminated directory %s", dir.Path)
+	}
+	return dir, nil
+}
+
+// readNcduInfo reads an info object whose opening brace has been consumed.
+// Unknown fields (dev, ino, hlnkc, read_error, …) are skipped.
+func readNcduInfo(dec *json.Decoder, parent *DirEntry) (*DirEntry, error) {
+	var info ncduInfo
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("ncdu export: %w", err)
+		}
+		key, _ := keyTok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("ncdu export: %w", err)
+		}
+		switch key {
+		case "name":
+			err = json.Unmarshal(raw, &info.Name)
+		case "asize":
+			err = json.Unmarshal(raw, &info.Asize)
+		case "dsize":
+			err = json.Unmarshal(raw, &info.Dsize)
+		case "mtime":
+			err = json.Unmarshal(raw, &info.Mtime)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ncdu export: field %q: %w", key, err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, errNcduFormat
+	}
+	if info.Name == "" {
+		return nil, fmt.Errorf("ncdu export: entry without name")
+	}
+
+	e := &DirEntry{
+		Name:    info.Name,
+		Size:    info.Asize,
+		Parent:  parent,
+		Scanned: true,
+	}
+	if e.Size == 0 {
+		e.Size = info.Dsize
+	}
+	if info.Mtime > 0 {
+		e.ModTime = time.Unix(info.Mtime, 0)
+	}
+	if parent != nil {
+		e.Path = filepath.Join(parent.Path, info.Name)
+	} else {
+		// The root carries its full path.
+		e.Path = info.Name
+		e.Name = filepath.Base(strings.TrimRight(filepath.FromSlash(info.Name), `\/`))
+		if e.Name == "." || e.Name == "" {
+			e.Name = info.Name
+		}
+	}
+	return e, nil
+}
+
+// expectDelim consumes the next token and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// ─── File Import ─────────────────────────────────────────────────────────────
+
+// ImportFile loads a tree from path, which may be an ncdu JSON export or
+// a WinMole analyze cache file.
+func ImportFile(path string) (*DirEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	first, err := firstNonSpace(br)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, errNcduFormat)
+	}
+
+	if first == '[' {
+		root, importErr := ImportNcdu(br)
+		if importErr != nil {
+			return nil, fmt.Errorf("%s: %w", path, importErr)
+		}
+		return root, nil
+	}
+
+	entry, decErr := decodeCache(br)
+	if decErr != nil || entry.Root == nil {
+		return nil, fmt.Errorf("%s: not an ncdu export or WinMole cache", path)
+	}
+	return entry.Root, nil
+}
+
+// firstNonSpace skips leading whitespace in br and returns the next byte
+// without consuming it.
+func firstNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = br.Discard(1)
+		default:
+			return b[0], nil
+		}
+	}
+}
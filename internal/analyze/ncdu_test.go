@@ -0,0 +1,138 @@
+package analyze
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleNcdu is a trimmed export as written by ncdu 1.19 in extended mode.
+const sampleNcdu = `[1,2,{"progname":"ncdu","progver":"1.19","timestamp":1700000000},
+[{"name":"/home/alice","asize":4096,"dsize":4096,"dev":2049,"ino":2},
+{"name":"notes.txt","asize":1200,"dsize":4096,"ino":12,"mtime":1690000000},
+[{"name":"videos","asize":4096,"dsize":4096,"ino":3},
+{"name":"trip.mp4","asize":5000000,"dsize":5001216,"ino":4,"hlnkc":true,"nlink":2}],
+[{"name":"locked","read_error":true}],
+{"name":"socket","notreg":true},
+{"name":".cache","excluded":"pattern"}]]
+`
+
+func findChild(e *DirEntry, name string) *DirEntry {
+	for _, c := range e.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestImportNcdu_Sample(t *testing.T) {
+	root, err := ImportNcdu(strings.NewReader(sampleNcdu))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if root.Path != "/home/alice" || root.Name != "alice" || !root.IsDir {
+		t.Errorf("root = %q (%q), want /home/alice (alice)", root.Path, root.Name)
+	}
+	if root.Size != 5001200 {
+		t.Errorf("root size = %d, want 5001200 (sum of apparent sizes)", root.Size)
+	}
+	if len(root.Children) != 5 || root.Children[0].Name != "videos" {
+		t.Errorf("children not sorted by size: %+v", root.Children)
+	}
+
+	notes := findChild(root, "notes.txt")
+	if notes == nil || !notes.ModTime.Equal(time.Unix(1690000000, 0)) || notes.Parent != root {
+		t.Errorf("notes.txt = %+v", notes)
+	}
+	video := findChild(findChild(root, "videos"), "trip.mp4")
+	if video == nil || video.Path != filepath.Join("/home/alice", "videos", "trip.mp4") {
+		t.Errorf("trip.mp4 = %+v", video)
+	}
+	if locked := findChild(root, "locked"); locked == nil || !locked.IsDir {
+		t.Errorf("unreadable directory should be kept as an empty dir, got %+v", locked)
+	}
+}
+
+func TestNcdu_ExportImportRoundTrip(t *testing.T) {
+	tree := syntheticTree(3, 4)
+	calculateSizes(tree)
+
+	var buf bytes.Buffer
+	if err := ExportNcdu(&buf, tree, "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), `[1,2,{"progname":"winmole","progver":"1.2.3"`) {
+		t.Errorf("unexpected header: %.60s", buf.String())
+	}
+
+	got, err := ImportNcdu(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path != tree.Path || got.Size != tree.Size {
+		t.Errorf("root = %q %d, want %q %d", got.Path, got.Size, tree.Path, tree.Size)
+	}
+
+	var count func(*DirEntry) int
+	count = func(e *DirEntry) int {
+		n := 1
+		for _, c := range e.Children {
+			n += count(c)
+		}
+		return n
+	}
+	if count(got) != count(tree) {
+		t.Errorf("imported %d entries, want %d", count(got), count(tree))
+	}
+}
+
+func TestImportNcdu_RejectsOtherVersions(t *testing.T) {
+	for _, in := range []string{
+		`[2,0,{},[{"name":"/"}]]`,
+		`{"not":"ncdu"}`,
+		`[1,2,{},[{"asize":1}]]`, // root without name
+	} {
+		if _, err := ImportNcdu(strings.NewReader(in)); err == nil {
+			t.Errorf("ImportNcdu(%s) should fail", in)
+		}
+	}
+}
+
+func TestImportFile_DetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+
+	ncduPath := filepath.Join(dir, "scan.json")
+	if err := os.WriteFile(ncduPath, []byte("\n  "+sampleNcdu), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := ImportFile(ncduPath); err != nil || root.Name != "alice" {
+		t.Errorf("ImportFile(ncdu) = %v, %v", root, err)
+	}
+
+	cacheFile := filepath.Join(dir, "scan.bin")
+	f, err := os.Create(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := syntheticTree(2, 2)
+	if err := encodeCache(f, cacheEntry{Timestamp: time.Now(), Root: tree}, true); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if root, err := ImportFile(cacheFile); err != nil || root.Size != tree.Size {
+		t.Errorf("ImportFile(cache) = %v, %v", root, err)
+	}
+
+	junk := filepath.Join(dir, "junk.txt")
+	if err := os.WriteFile(junk, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportFile(junk); err == nil {
+		t.Error("ImportFile(junk) should fail")
+	}
+}
@@ -0,0 +1,140 @@
+package analyze
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ─── Headless Report ─────────────────────────────────────────────────────────
+// A report lists the largest directories and files of a scanned tree for
+// scripts and scheduled jobs, as an alternative to the TUI.
+
+// ReportOptions limits what a report includes.
+type ReportOptions struct {
+	Top     int   // entries per list
+	Depth   int   // levels below the root to consider; 0 for no limit
+	MinSize int64 // smallest entry to include
+}
+
+// ReportItem is one listed directory or file.
+type ReportItem struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Percent float64   `json:"percent"`
+	Depth   int       `json:"depth"`
+	ModTime time.Time `json:"mod_time,omitzero"`
+}
+
+// Report is the result of BuildReport.
+type Report struct {
+	Root        string       `json:"root"`
+	Size        int64        `json:"size"`
+	Generated   time.Time    `json:"generated"`
+	Directories []ReportItem `json:"directories"`
+	Files       []ReportItem `json:"files"`
+}
+
+// BuildReport collects the opts.Top largest directories and files under
+// root, each sorted largest first. The root itself is not listed.
+func BuildReport(root *DirEntry, opts ReportOptions) Report {
+	r := Report{
+		Root:        root.Path,
+		Size:        root.Size,
+		Generated:   time.Now(),
+		Directories: []ReportItem{},
+		Files:       []ReportItem{},
+	}
+	if opts.Top <= 0 {
+		return r
+	}
+
+	dirs, files := &entryHeap{}, &entryHeap{}
+
+	var walk func(e *DirEntry, depth int)
+	walk = func(e *DirEntry, depth int) {
+		if opts.Depth > 0 && depth > opts.Depth {
+			return
+		}
+		// Children are no larger than their parent, so a small directory
+		// cannot hold anything worth listing.
+		if e.Size < opts.MinSize {
+			return
+		}
+		if depth > 0 {
+			if e.IsDir {
+				dirs.offer(e, opts.Top)
+			} else {
+				files.offer(e, opts.Top)
+			}
+		}
+		for _, c := range e.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(root, 0)
+
+	item := func(e *DirEntry) ReportItem {
+		return ReportItem{
+			Path:    e.Path,
+			Size:    e.Size,
+			Percent: e.Percentage(root.Size),
+			Depth:   depthBelow(e, root),
+			ModTime: e.ModTime,
+		}
+	}
+	for _, e := range dirs.sorted() {
+		r.Directories = append(r.Directories, item(e))
+	}
+	for _, e := range files.sorted() {
+		r.Files = append(r.Files, item(e))
+	}
+	return r
+}
+
+// depthBelow returns how many levels e is below root.
+func depthBelow(e, root *DirEntry) int {
+	d := 0
+	for ; e != nil && e != root; e = e.Parent {
+		d++
+	}
+	return d
+}
+
+// WriteJSON writes r as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes r as CSV with one row per item, directories first.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"type", "path", "size", "percent", "depth", "modified"})
+
+	write := func(kind string, items []ReportItem) {
+		for _, it := range items {
+			modified := ""
+			if !it.ModTime.IsZero() {
+				modified = it.ModTime.UTC().Format(time.RFC3339)
+			}
+			_ = cw.Write([]string{
+				kind,
+				it.Path,
+				strconv.FormatInt(it.Size, 10),
+				fmt.Sprintf("%.2f", it.Percent),
+				strconv.Itoa(it.Depth),
+				modified,
+			})
+		}
+	}
+	write("dir", r.Directories)
+	write("file", r.Files)
+
+	cw.Flush()
+	return cw.Error()
+}
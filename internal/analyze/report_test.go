@@ -0,0 +1,65 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildReport(t *testing.T) {
+	root := typedTree() // media/{videos/{a.mp4,b.MKV,notes.txt}, isos/{win.iso,old/}, c.mp4, LICENSE}
+
+	r := BuildReport(root, ReportOptions{Top: 2})
+	if len(r.Directories) != 2 || r.Directories[0].Path != findChild(root, "videos").Path {
+		t.Errorf("directories = %+v", r.Directories)
+	}
+	if len(r.Files) != 2 || r.Files[0].Size != 5000 || r.Files[0].Depth != 2 {
+		t.Errorf("files = %+v", r.Files)
+	}
+
+	// Depth 1 only sees the root's own children.
+	r = BuildReport(root, ReportOptions{Top: 10, Depth: 1})
+	if len(r.Files) != 2 || r.Files[0].Path != findChild(root, "c.mp4").Path {
+		t.Errorf("depth 1 files = %+v", r.Files)
+	}
+
+	// Min size prunes small entries and their subtrees.
+	r = BuildReport(root, ReportOptions{Top: 10, MinSize: 3000})
+	if len(r.Files) != 3 || len(r.Directories) != 2 {
+		t.Errorf("min-size report = %d dirs %d files", len(r.Directories), len(r.Files))
+	}
+	for _, it := range append(r.Directories, r.Files...) {
+		if it.Size < 3000 {
+			t.Errorf("%s (%d bytes) is below --min-size", it.Path, it.Size)
+		}
+	}
+}
+
+func TestReport_Formats(t *testing.T) {
+	r := BuildReport(typedTree(), ReportOptions{Top: 3})
+
+	var js bytes.Buffer
+	if err := r.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var back Report
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Root != r.Root || len(back.Files) != len(r.Files) {
+		t.Errorf("JSON round trip = %+v", back)
+	}
+
+	var cs bytes.Buffer
+	if err := r.WriteCSV(&cs); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&cs).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1+len(r.Directories)+len(r.Files) || rows[0][0] != "type" || rows[1][0] != "dir" {
+		t.Errorf("CSV rows = %v", rows)
+	}
+}
@@ -41,8 +41,11 @@ type Scanner struct {
 	sem          chan struct{}
 	exclude      map[string]bool
 	mu           sync.Mutex
+	tree         sync.RWMutex // guards the tree while ScanLive fills it
 	warnings     []string
 	scannedCount atomic.Int64
+	reusedDirs   atomic.Int64
+	readDirs     atomic.Int64
 }
 
 // NewScanner creates a scanner with bounded concurrency.
@@ -73,6 +76,22 @@ func (s *Scanner) ScannedCount() int64 {
 	return s.scannedCount.Load()
 }
 
+// RLock holds off tree updates from a running ScanLive so the partial
+// tree can be read consistently. Pair each call with RUnlock.
+func (s *Scanner) RLock() { s.tree.RLock() }
+
+// RUnlock releases a lock taken with RLock.
+func (s *Scanner) RUnlock() { s.tree.RUnlock() }
+
+// RLocker returns a sync.Locker for RLock and RUnlock.
+func (s *Scanner) RLocker() sync.Locker { return s.tree.RLocker() }
+
+// CacheStats returns how many directories were reused unchanged from the
+// previous tree and how many had to be read from disk (see ScanIncremental).
+func (s *Scanner) CacheStats() (reused, read int64) {
+	return s.reusedDirs.Load(), s.readDirs.Load()
+}
+
 func (s *Scanner) addWarning(msg string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -106,36 +125,92 @@ func longPath(path string) string {
 
 // Scan performs a parallel recursive scan of the given root path.
 func (s *Scanner) Scan(rootPath string) (*DirEntry, error) {
+	return s.ScanIncremental(rootPath, nil)
+}
+
+// ScanIncremental rescans rootPath, reusing the parts of prev (an earlier
+// scan of the same root, e.g. from LoadCache) that are still current. A
+// directory whose modification time is unchanged has the same children,
+// so its file entries are taken from prev without reading the directory;
+// only its subdirectories are stat'ed and revisited. Directories whose
+// mtime changed are read from disk. File sizes that changed in place
+// without touching the directory are not detected; force a full Scan for
+// those. prev may be nil.
+func (s *Scanner) ScanIncremental(rootPath string, prev *DirEntry) (*DirEntry, error) {
+	root, done, err := s.ScanLive(rootPath, prev)
+	if err != nil {
+		return nil, err
+	}
+	<-done
+	return root, nil
+}
+
+// ScanLive starts ScanIncremental in the background and returns the root
+// at once; done is closed when the scan has finished. Until then the tree
+// grows as directories are read: sizes are provisional sums of the files
+// found so far, and directories still being read have Scanned unset.
+// Readers must hold RLock while walking the tree before done is closed.
+func (s *Scanner) ScanLive(rootPath string, prev *DirEntry) (root *DirEntry, done <-chan struct{}, err error) {
 	rootPath = filepath.Clean(rootPath)
 
 	info, err := os.Lstat(longPath(rootPath))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	root := &DirEntry{
+	root = &DirEntry{
 		Path:    rootPath,
 		Name:    info.Name(),
 		IsDir:   info.IsDir(),
 		ModTime: info.ModTime(),
 	}
+	finished := make(chan struct{})
 
 	if !info.IsDir() {
 		root.Size = info.Size()
 		root.Scanned = true
-		return root, nil
+		close(finished)
+		return root, finished, nil
 	}
 
-	s.scanDir(root)
-	s.calculateSizes(root)
-	root.Scanned = true
+	if prev != nil && !strings.EqualFold(filepath.Clean(prev.Path), rootPath) {
+		prev = nil
+	}
 
-	return root, nil
+	go func() {
+		defer close(finished)
+		s.scanDir(root, prev)
+
+		s.tree.Lock()
+		calculateSizes(root)
+		root.Scanned = true
+		s.tree.Unlock()
+	}()
+
+	return root, finished, nil
 }
 
 // scanDir recursively scans a directory, using the semaphore only during I/O
-// to prevent deadlocks from nested goroutine semaphore acquisition.
-func (s *Scanner) scanDir(entry *DirEntry) {
+// to prevent deadlocks from nested goroutine semaphore acquisition. prev is
+// the same directory in an earlier scan, or nil.
+func (s *Scanner) scanDir(entry *DirEntry, prev *DirEntry) {
+	if prev != nil && prev.IsDir && prev.Scanned && prev.ModTime.Equal(entry.ModTime) {
+		s.reuseDir(entry, prev)
+		return
+	}
+	s.readDirs.Add(1)
+
+	// Earlier subdirectories by name, to revisit incrementally.
+	var prevDirs map[string]*DirEntry
+	if prev != nil {
+		prevDirs = make(map[string]*DirEntry, len(prev.Children))
+		for _, c := range prev.Children {
+			if c.IsDir {
+				prevDirs[c.Name] = c
+			}
+		}
+	}
+
 	dirPath := longPath(entry.Path)
 
 	// Hold semaphore only during the ReadDir I/O.
@@ -149,7 +224,8 @@ func (s *Scanner) scanDir(entry *DirEntry) {
 	}
 
 	var wg sync.WaitGroup
-	var mu sync.Mutex
+	var children []*DirEntry
+	var files int64
 
 	for _, e := range entries {
 		childPath := filepath.Join(entry.Path, e.Name())
@@ -184,33 +260,110 @@ func (s *Scanner) scanDir(entry *DirEntry) {
 		if !e.IsDir() {
 			child.Size = info.Size()
 			child.Scanned = true
+			files += child.Size
 		} else {
 			wg.Add(1)
-			go func(dir *DirEntry) {
+			go func(dir, prevDir *DirEntry) {
 				defer wg.Done()
-				s.scanDir(dir)
-				dir.Scanned = true
-			}(child)
+				s.scanDir(dir, prevDir)
+				s.finishDir(dir)
+			}(child, prevDirs[e.Name()])
+		}
+		children = append(children, child)
+	}
+
+	s.publish(entry, children, files)
+	wg.Wait()
+}
+
+// reuseDir fills entry from prev, the unchanged directory in an earlier
+// scan. Files are adopted as-is; subdirectories are stat'ed and revisited
+// since changes deeper down do not alter this directory's mtime.
+func (s *Scanner) reuseDir(entry *DirEntry, prev *DirEntry) {
+	s.reusedDirs.Add(1)
+
+	var wg sync.WaitGroup
+	var children []*DirEntry
+	var files int64
+
+	for _, pc := range prev.Children {
+		s.scannedCount.Add(1)
+
+		if !pc.IsDir {
+			pc.Parent = entry
+			children = append(children, pc)
+			files += pc.Size
+			continue
 		}
 
-		mu.Lock()
-		entry.Children = append(entry.Children, child)
-		mu.Unlock()
+		// The exclude list may differ from the earlier scan.
+		if s.exclude[strings.ToLower(pc.Name)] {
+			continue
+		}
+
+		s.sem <- struct{}{}
+		info, err := os.Lstat(longPath(pc.Path))
+		<-s.sem
+		if err != nil {
+			s.addWarning("cannot stat " + pc.Path + ": " + err.Error())
+			continue
+		}
+
+		child := &DirEntry{
+			Path:    pc.Path,
+			Name:    pc.Name,
+			IsDir:   true,
+			Parent:  entry,
+			ModTime: info.ModTime(),
+		}
+
+		wg.Add(1)
+		go func(dir, prevDir *DirEntry) {
+			defer wg.Done()
+			s.scanDir(dir, prevDir)
+			s.finishDir(dir)
+		}(child, pc)
+		children = append(children, child)
 	}
 
+	s.publish(entry, children, files)
 	wg.Wait()
 }
 
+// publish attaches the children read for entry to the tree and adds the
+// size of its files to entry and every ancestor, so a live tree shows
+// provisional sizes while subdirectories are still being read.
+func (s *Scanner) publish(entry *DirEntry, children []*DirEntry, files int64) {
+	s.tree.Lock()
+	defer s.tree.Unlock()
+	entry.Children = append(entry.Children, children...)
+	for p := entry; p != nil; p = p.Parent {
+		p.Size += files
+	}
+}
+
+// finishDir marks dir complete once its whole subtree has been read. Its
+// size is final by then, so its children are sorted as calculateSizes
+// would.
+func (s *Scanner) finishDir(dir *DirEntry) {
+	s.tree.Lock()
+	defer s.tree.Unlock()
+	sort.Slice(dir.Children, func(i, j int) bool {
+		return dir.Children[i].Size > dir.Children[j].Size
+	})
+	dir.Scanned = true
+}
+
 // calculateSizes walks the tree bottom-up, summing sizes from children,
 // then sorts each level by size descending.
-func (s *Scanner) calculateSizes(entry *DirEntry) {
+func calculateSizes(entry *DirEntry) {
 	if !entry.IsDir {
 		return
 	}
 
 	var total int64
 	for _, child := range entry.Children {
-		s.calculateSizes(child)
+		calculateSizes(child)
 		total += child.Size
 	}
 	entry.Size = total
@@ -0,0 +1,150 @@
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFile creates path (and its parents) with size bytes.
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanIncremental_ReusesUnchangedDirs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a", "f1"), 100)
+	writeFile(t, filepath.Join(root, "b", "c", "f2"), 200)
+
+	prev, err := NewScanner(2, nil).Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Adding a file changes a's mtime; force a distinct value.
+	writeFile(t, filepath.Join(root, "a", "f3"), 50)
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "a"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner(2, nil)
+	tree, err := s.ScanIncremental(root, prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reused, read := s.CacheStats()
+	if reused != 3 || read != 1 { // root, b, b/c reused; a read
+		t.Errorf("CacheStats = %d reused, %d read; want 3, 1", reused, read)
+	}
+	if tree.Size != 350 {
+		t.Errorf("root size = %d, want 350", tree.Size)
+	}
+	for _, c := range tree.Children {
+		if c.Parent != tree {
+			t.Errorf("child %s has wrong parent after reuse", c.Name)
+		}
+	}
+}
+
+func TestScanIncremental_NilOrForeignPrevIsFullScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "x", "f"), 10)
+
+	other := &DirEntry{Path: filepath.Join(root, "elsewhere"), IsDir: true, Scanned: true}
+
+	for _, prev := range []*DirEntry{nil, other} {
+		s := NewScanner(2, nil)
+		tree, err := s.ScanIncremental(root, prev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reused, read := s.CacheStats(); reused != 0 || read != 2 {
+			t.Errorf("CacheStats = %d reused, %d read; want 0, 2", reused, read)
+		}
+		if tree.Size != 10 {
+			t.Errorf("root size = %d, want 10", tree.Size)
+		}
+	}
+}
+
+func TestScanIncremental_AppliesCurrentExcludes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "keep", "f"), 10)
+	writeFile(t, filepath.Join(root, "node_modules", "f"), 1000)
+
+	prev, err := NewScanner(2, nil).Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := NewScanner(2, []string{"node_modules"}).ScanIncremental(root, prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Size != 10 {
+		t.Errorf("root size = %d, want 10 with node_modules excluded", tree.Size)
+	}
+}
+
+func TestScanLive_PartialTreeIsConsistent(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"a", "a/b", "a/b/c", "d", "e/f"} {
+		writeFile(t, filepath.Join(root, d, "f"), 100)
+	}
+
+	s := NewScanner(2, nil)
+	tree, done, err := s.ScanLive(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Walk the tree while it is being filled: every directory's
+	// provisional size must be at least the sum of what it lists.
+	var check func(e *DirEntry) int64
+	check = func(e *DirEntry) int64 {
+		var sum int64
+		for _, c := range e.Children {
+			if c.Parent != e {
+				t.Errorf("%s has wrong parent", c.Path)
+			}
+			sum += check(c)
+		}
+		if e.IsDir && e.Size < sum {
+			t.Errorf("%s: size %d below listed %d", e.Path, e.Size, sum)
+		}
+		return e.Size
+	}
+	for scanning := true; scanning; {
+		select {
+		case <-done:
+			scanning = false
+		default:
+		}
+		s.RLock()
+		check(tree)
+		s.RUnlock()
+	}
+
+	if !tree.Scanned || tree.Size != 500 {
+		t.Errorf("finished tree: scanned=%v size=%d, want true, 500", tree.Scanned, tree.Size)
+	}
+	var unscanned func(e *DirEntry)
+	unscanned = func(e *DirEntry) {
+		if !e.Scanned {
+			t.Errorf("%s not marked scanned", e.Path)
+		}
+		for _, c := range e.Children {
+			unscanned(c)
+		}
+	}
+	unscanned(tree)
+}
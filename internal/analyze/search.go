@@ -0,0 +1,151 @@
+package analyze
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Search ──────────────────────────────────────────────────────────────────
+
+// SearchTree returns up to limit entries anywhere under root whose name
+// matches query, largest first. A query containing *, ? or [ is a glob
+// matched against the whole name; otherwise it matches any part of the
+// name. Matching is case-insensitive, as names are on Windows.
+func SearchTree(root *DirEntry, query string, limit int) []*DirEntry {
+	return searchTree(root, query, limit, nil)
+}
+
+// searchTree is SearchTree for a tree that may still be growing: lock,
+// if not nil, is held while each directory is read rather than for the
+// whole walk, so a live scan and the TUI are only held up briefly.
+func searchTree(root *DirEntry, query string, limit int, lock sync.Locker) []*DirEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if root == nil || query == "" || limit <= 0 {
+		return nil
+	}
+
+	match := func(name string) bool { return strings.Contains(name, query) }
+	if strings.ContainsAny(query, "*?[") {
+		if _, err := filepath.Match(query, ""); err != nil {
+			return nil // incomplete pattern while typing, e.g. "[a"
+		}
+		match = func(name string) bool {
+			ok, _ := filepath.Match(query, name)
+			return ok
+		}
+	}
+
+	if lock == nil {
+		lock = noLock{}
+	}
+
+	h := &entryHeap{}
+	dirs := []*DirEntry{root}
+	for len(dirs) > 0 {
+		e := dirs[len(dirs)-1]
+		dirs = dirs[:len(dirs)-1]
+
+		lock.Lock()
+		for _, c := range e.Children {
+			if match(strings.ToLower(c.Name)) {
+				h.offer(c, limit)
+			}
+			if c.IsDir {
+				dirs = append(dirs, c)
+			}
+		}
+		lock.Unlock()
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	return h.sorted()
+}
+
+// noLock is a sync.Locker for trees that are no longer changing.
+type noLock struct{}
+
+func (noLock) Lock()   {}
+func (noLock) Unlock() {}
+
+// ─── View Filter ─────────────────────────────────────────────────────────────
+
+// ViewFilter narrows the entries listed for the current directory. The
+// zero value shows everything.
+type ViewFilter struct {
+	MinSize   int64     // hide entries smaller than this
+	OlderThan time.Time // hide entries modified at or after this time
+	Kind      FileKind  // hide files of other kinds; directories stay
+}
+
+// Active reports whether any criterion is set.
+func (f ViewFilter) Active() bool {
+	return f.MinSize > 0 || !f.OlderThan.IsZero() || f.Kind != ""
+}
+
+// Match reports whether e passes the filter. Directories are kept by the
+// kind criterion so the matching files inside them can still be reached.
+func (f ViewFilter) Match(e *DirEntry) bool {
+	if e.Size < f.MinSize {
+		return false
+	}
+	if !f.OlderThan.IsZero() && !e.ModTime.Before(f.OlderThan) {
+		return false
+	}
+	if f.Kind != "" && !e.IsDir && KindOf(e.Name) != f.Kind {
+		return false
+	}
+	return true
+}
+
+// Filter presets, cycled through from the TUI.
+var (
+	minSizePresets = []int64{0, 10 << 20, 100 << 20, 1 << 30, 10 << 30}
+
+	// olderPresets are ages; 180 days matches DirEntry.IsOld.
+	olderPresets = []time.Duration{0, 30 * 24 * time.Hour, 180 * 24 * time.Hour, 365 * 24 * time.Hour, 2 * 365 * 24 * time.Hour}
+
+	kindPresets = []FileKind{"", KindVideo, KindAudio, KindImage, KindArchive,
+		KindDiskImage, KindInstaller, KindCode, KindDocument, KindOther}
+)
+
+// nextPreset returns the preset after cur, wrapping around to the first.
+func nextPreset[T comparable](presets []T, cur T) T {
+	for i, p := range presets {
+		if p == cur {
+			return presets[(i+1)%len(presets)]
+		}
+	}
+	return presets[0]
+}
+
+// Describe summarises the active criteria, e.g. "≥1 GB, >6mo, video".
+// sizeFmt formats byte counts.
+func (f ViewFilter) Describe(age time.Duration, sizeFmt func(int64) string) string {
+	var parts []string
+	if f.MinSize > 0 {
+		parts = append(parts, "≥"+sizeFmt(f.MinSize))
+	}
+	if age > 0 {
+		parts = append(parts, ">"+describeAge(age))
+	}
+	if f.Kind != "" {
+		parts = append(parts, string(f.Kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeAge formats a preset age compactly.
+func describeAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days >= 365:
+		return fmt.Sprintf("%dy", days/365)
+	case days >= 30:
+		return fmt.Sprintf("%dmo", days/30)
+	}
+	return fmt.Sprintf("%dd", days)
+}
@@ -0,0 +1,100 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchTree(t *testing.T) {
+	root := typedTree()
+
+	names := func(es []*DirEntry) []string {
+		var out []string
+		for _, e := range es {
+			out = append(out, e.Name)
+		}
+		return out
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"MP4", []string{"a.mp4", "c.mp4"}}, // substring, case-insensitive, by size
+		{"*.iso", []string{"win.iso"}},      // glob over the whole name
+		{"VID", []string{"videos"}},         // directories too
+		{"[a", nil},                         // incomplete glob
+		{"  ", nil},
+	} {
+		got := names(SearchTree(root, tc.query, 4))
+		if len(got) != len(tc.want) {
+			t.Errorf("SearchTree(%q) = %v, want %v", tc.query, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("SearchTree(%q) = %v, want %v", tc.query, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+// countingLock records how often searchTree takes and releases it.
+type countingLock struct{ held, locks int }
+
+func (l *countingLock) Lock()   { l.held++; l.locks++ }
+func (l *countingLock) Unlock() { l.held-- }
+
+func TestSearchTree_LocksPerDirectory(t *testing.T) {
+	lock := &countingLock{}
+	got := searchTree(typedTree(), "mp4", 10, lock)
+
+	if len(got) != 2 || got[0].Name != "a.mp4" {
+		t.Errorf("results = %v", got)
+	}
+	// root, videos, isos, isos/old, then the final sort.
+	if lock.held != 0 || lock.locks != 5 {
+		t.Errorf("lock held %d, taken %d times; want 0, 5", lock.held, lock.locks)
+	}
+}
+
+func TestViewFilter(t *testing.T) {
+	now := time.Now()
+	old := &DirEntry{Name: "old.iso", Size: 2 << 30, ModTime: now.AddDate(-1, 0, 0)}
+	fresh := &DirEntry{Name: "new.mp4", Size: 50 << 20, ModTime: now}
+	dir := &DirEntry{Name: "stuff", IsDir: true, Size: 3 << 30, ModTime: now.AddDate(-2, 0, 0)}
+
+	for _, tc := range []struct {
+		name   string
+		filter ViewFilter
+		want   [3]bool // old, fresh, dir
+	}{
+		{"none", ViewFilter{}, [3]bool{true, true, true}},
+		{"min size", ViewFilter{MinSize: 1 << 30}, [3]bool{true, false, true}},
+		{"older than 6 months", ViewFilter{OlderThan: now.AddDate(0, -6, 0)}, [3]bool{true, false, true}},
+		{"video only keeps dirs", ViewFilter{Kind: KindVideo}, [3]bool{false, true, true}},
+	} {
+		for i, e := range []*DirEntry{old, fresh, dir} {
+			if got := tc.filter.Match(e); got != tc.want[i] {
+				t.Errorf("%s: Match(%s) = %v, want %v", tc.name, e.Name, got, tc.want[i])
+			}
+		}
+	}
+
+	if (ViewFilter{}).Active() || !(ViewFilter{Kind: KindCode}).Active() {
+		t.Error("Active() mismatch")
+	}
+}
+
+func TestNextPreset(t *testing.T) {
+	if got := nextPreset(minSizePresets, 0); got != 10<<20 {
+		t.Errorf("next after off = %d", got)
+	}
+	if got := nextPreset(minSizePresets, minSizePresets[len(minSizePresets)-1]); got != 0 {
+		t.Errorf("presets should wrap to off, got %d", got)
+	}
+	if got := nextPreset(kindPresets, FileKind("bogus")); got != "" {
+		t.Errorf("unknown value should reset, got %q", got)
+	}
+}
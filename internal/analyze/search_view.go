@@ -0,0 +1,248 @@
+package analyze
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+)
+
+// ─── Search Mode ─────────────────────────────────────────────────────────────
+// "/" opens a prompt that searches the whole tree as the user types. The
+// results replace the body, largest first, and Enter jumps to the selected
+// entry in the tree view. Searches run in the background once typing
+// pauses, so large trees do not stall the prompt.
+
+const (
+	searchLimit    = 500                    // caps the number of results kept
+	searchDebounce = 150 * time.Millisecond // pause in typing before a search
+)
+
+// searchState is the search prompt and its results.
+type searchState struct {
+	active  bool
+	query   string
+	results []*DirEntry
+	cursor  int
+	offset  int
+	seq     int  // bumped per query; stale searches are dropped
+	busy    bool // a search for query is pending
+}
+
+type (
+	searchDueMsg    struct{ seq int }
+	searchResultMsg struct {
+		seq     int
+		results []*DirEntry
+	}
+)
+
+// updateSearch handles keys while the search prompt is open.
+func (m AnalyzeModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.search
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.search = searchState{}
+
+	case tea.KeyEnter:
+		if s.cursor < len(s.results) {
+			target := s.results[s.cursor]
+			m.search = searchState{}
+			m.jumpTo(target)
+		}
+
+	case tea.KeyUp:
+		s.cursor = max(s.cursor-1, 0)
+
+	case tea.KeyDown:
+		s.cursor = max(min(s.cursor+1, len(s.results)-1), 0)
+
+	case tea.KeyBackspace:
+		if r := []rune(s.query); len(r) > 0 {
+			s.query = string(r[:len(r)-1])
+			return m, m.queueSearch()
+		}
+
+	case tea.KeyCtrlU:
+		s.query = ""
+		return m, m.queueSearch()
+
+	case tea.KeyRunes, tea.KeySpace:
+		s.query += string(msg.Runes)
+		return m, m.queueSearch()
+	}
+
+	s.offset = scrollOffset(s.cursor, s.offset, m.viewportHeight()-1)
+	return m, nil
+}
+
+// queueSearch schedules a search for the current query once typing
+// pauses, superseding any search still pending.
+func (m *AnalyzeModel) queueSearch() tea.Cmd {
+	s := &m.search
+	s.seq++
+	if strings.TrimSpace(s.query) == "" {
+		s.results, s.busy = nil, false
+		s.cursor, s.offset = 0, 0
+		return nil
+	}
+	s.busy = true
+	seq := s.seq
+	return tea.Tick(searchDebounce, func(time.Time) tea.Msg { return searchDueMsg{seq} })
+}
+
+// runSearch searches the tree in the background for the current query.
+func (m AnalyzeModel) runSearch() tea.Cmd {
+	root, query, seq := m.root, m.search.query, m.search.seq
+	var lock sync.Locker
+	if m.live != nil {
+		lock = m.live.scanner.RLocker()
+	}
+	return func() tea.Msg {
+		return searchResultMsg{seq: seq, results: searchTree(root, query, searchLimit, lock)}
+	}
+}
+
+// updateSearchMsg handles the debounce timer and finished searches;
+// anything for an outdated query is dropped.
+func (m AnalyzeModel) updateSearchMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case searchDueMsg:
+		if m.search.active && msg.seq == m.search.seq {
+			return m, m.runSearch()
+		}
+	case searchResultMsg:
+		if m.search.active && msg.seq == m.search.seq {
+			m.search.results, m.search.busy = msg.results, false
+			m.search.cursor, m.search.offset = 0, 0
+		}
+	}
+	return m, nil
+}
+
+// jumpTo shows e's directory in the tree view with e selected. Filters
+// that would hide e are cleared.
+func (m *AnalyzeModel) jumpTo(e *DirEntry) {
+	if e.Parent == nil {
+		return
+	}
+	var chain []*DirEntry
+	for p := e.Parent; p != nil; p = p.Parent {
+		chain = append([]*DirEntry{p}, chain...)
+		if p == m.root {
+			break
+		}
+	}
+	if chain[0] != m.root {
+		return
+	}
+
+	m.tab = tabTree
+	m.breadcrumb = chain[:len(chain)-1]
+	m.current = e.Parent
+	if !m.filter.Match(e) {
+		m.filter, m.filterAge = ViewFilter{}, 0
+	}
+	m.cursor, m.offset = 0, 0
+	m.selectEntry(e)
+	m.ensureVisible()
+}
+
+// ─── Filters ─────────────────────────────────────────────────────────────────
+
+// updateFilter applies a filter key and reports whether key was one.
+func (m *AnalyzeModel) updateFilter(key string) bool {
+	switch key {
+	case "L":
+		// Shortcut for the original large-files-only toggle.
+		if m.filter.MinSize == 100<<20 {
+			m.filter.MinSize = 0
+		} else {
+			m.filter.MinSize = 100 << 20
+		}
+	case "s":
+		m.filter.MinSize = nextPreset(minSizePresets, m.filter.MinSize)
+	case "o":
+		m.filterAge = nextPreset(olderPresets, m.filterAge)
+		m.filter.OlderThan = time.Time{}
+		if m.filterAge > 0 {
+			m.filter.OlderThan = time.Now().Add(-m.filterAge)
+		}
+	case "y":
+		m.filter.Kind = nextPreset(kindPresets, m.filter.Kind)
+	case "F":
+		m.filter, m.filterAge = ViewFilter{}, 0
+	default:
+		return false
+	}
+	m.cursor = 0
+	m.offset = 0
+	return true
+}
+
+// filterTag renders the active filters for the footer.
+func (m AnalyzeModel) filterTag() string {
+	return ui.TagWarningStyle().Render(" filter: " + m.filter.Describe(m.filterAge, ui.FormatSize) + " ")
+}
+
+// ─── Search Rendering ────────────────────────────────────────────────────────
+
+func (m AnalyzeModel) renderSearch(w int) string {
+	s := m.search
+
+	prompt := lipgloss.NewStyle().Foreground(ui.ColorCoral).Bold(true).Render("  / ") +
+		s.query + lipgloss.NewStyle().Foreground(clrCursor).Render("▏")
+	count := ""
+	switch {
+	case s.query == "":
+		count = "type a name or a glob like *.iso"
+	case s.busy:
+		count = "searching…"
+	case len(s.results) == searchLimit:
+		count = fmt.Sprintf("%d+ matches", searchLimit)
+	default:
+		count = fmt.Sprintf("%d matches", len(s.results))
+	}
+	lines := []string{prompt + "  " + lipgloss.NewStyle().Foreground(ui.ColorMuted).Render(count)}
+
+	maxName := max(w/3, 12)
+	vh := m.viewportHeight() - 1
+	for i := s.offset; i < len(s.results) && i < s.offset+vh; i++ {
+		e := s.results[i]
+
+		icon := ui.IconBullet + " "
+		nameColor := kindColor(e)
+		if e.IsDir {
+			icon = ui.IconFolder
+		}
+		name := e.Name
+		if len(name) > maxName {
+			name = name[:maxName-1] + "…"
+		}
+
+		// Location relative to the scanned root.
+		dir := filepath.Dir(e.Path)
+		if rel, err := filepath.Rel(m.root.Path, dir); err == nil {
+			dir = rel
+		}
+
+		line := fmt.Sprintf("  %s %10s  %s %s  %s",
+			lipgloss.NewStyle().Foreground(clrDim).Render(fmt.Sprintf("%3d.", i+1)),
+			ui.FormatSize(e.Size),
+			icon,
+			lipgloss.NewStyle().Foreground(nameColor).Bold(e.IsDir).Render(name),
+			lipgloss.NewStyle().Foreground(clrDim).Render(dir))
+		lines = append(lines, withCursor(line, i == s.cursor))
+	}
+	return strings.Join(lines, "\n")
+}
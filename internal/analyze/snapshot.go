@@ -0,0 +1,140 @@
+package analyze
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ─── Snapshots ───────────────────────────────────────────────────────────────
+// A snapshot is a scan kept under a name in %APPDATA%\winmole\snapshots,
+// in the cache file format, so later scans can be compared against it.
+// Unlike the cache it never expires and is not replaced by the next scan.
+
+const (
+	snapshotDirName = "snapshots"
+	snapshotExt     = ".bin"
+
+	// snapshotTimeFormat names unnamed snapshots by when they were taken.
+	snapshotTimeFormat = "2006-01-02_150405"
+)
+
+// SnapshotInfo describes a saved snapshot.
+type SnapshotInfo struct {
+	Name  string
+	Path  string
+	Taken time.Time // file modification time
+	Bytes int64     // file size on disk
+}
+
+// snapshotDir returns the snapshot directory, creating it if needed.
+func snapshotDir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, snapshotDirName)
+	return dir, os.MkdirAll(dir, 0o755)
+}
+
+// validSnapshotName rejects names that are not a plain file name.
+func validSnapshotName(name string) error {
+	if name == "" || name == "." || name == ".." ||
+		strings.ContainsAny(name, `\/:*?"<>|`) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
+
+// SaveSnapshot stores the tree under root as a snapshot called name, or
+// named by the current time if name is empty, and returns the name used.
+// An existing snapshot with the same name is replaced.
+func SaveSnapshot(root *DirEntry, name string) (string, error) {
+	if name == "" {
+		name = time.Now().Format(snapshotTimeFormat)
+	}
+	if err := validSnapshotName(name); err != nil {
+		return "", err
+	}
+	dir, err := snapshotDir()
+	if err != nil {
+		return "", err
+	}
+
+	entry := cacheEntry{Timestamp: time.Now(), RootPath: root.Path, Root: root}
+	return name, writeCacheFile(filepath.Join(dir, name+snapshotExt), entry)
+}
+
+// ListSnapshots returns the saved snapshots, oldest first.
+func ListSnapshots() ([]SnapshotInfo, error) {
+	dir, err := snapshotDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []SnapshotInfo
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != snapshotExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, SnapshotInfo{
+			Name:  strings.TrimSuffix(e.Name(), snapshotExt),
+			Path:  filepath.Join(dir, e.Name()),
+			Taken: info.ModTime(),
+			Bytes: info.Size(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Taken.Before(out[j].Taken) })
+	return out, nil
+}
+
+// LoadSnapshot loads a tree by snapshot name, or from a file path (a
+// snapshot, cache or ncdu export) if no snapshot has that name. The
+// returned time is when the tree was saved, or zero if unknown.
+func LoadSnapshot(ref string) (*DirEntry, time.Time, error) {
+	path := ref
+	if validSnapshotName(ref) == nil {
+		if dir, err := snapshotDir(); err == nil {
+			if p := filepath.Join(dir, ref+snapshotExt); fileExists(p) {
+				path = p
+			}
+		}
+	}
+	if !fileExists(path) {
+		return nil, time.Time{}, fmt.Errorf("no snapshot or file named %q", ref)
+	}
+
+	// Snapshots and caches carry their timestamp; imports may not.
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	if first, err := firstNonSpace(br); err == nil && first != '[' {
+		entry, err := decodeCache(br)
+		if err == nil && entry.Root != nil {
+			return entry.Root, entry.Timestamp, nil
+		}
+	}
+
+	root, err := ImportFile(path)
+	return root, time.Time{}, err
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,185 @@
+package analyze
+
+import "math"
+
+// ─── Squarified Treemap Layout ───────────────────────────────────────────────
+// Children of the current directory are laid out as rectangles whose areas
+// are proportional to their sizes, using the squarified algorithm (Bruls,
+// Huizing & van Wijk) to keep rectangles close to square. Layout happens in
+// floating point and is then snapped to terminal cells; since a cell is
+// about twice as tall as it is wide, heights are doubled while laying out.
+
+// cellAspect is the height of a terminal cell relative to its width.
+const cellAspect = 2.0
+
+// tmRect is a rectangle in terminal cells.
+type tmRect struct {
+	x, y, w, h int
+}
+
+// tmTile is a laid-out entry.
+type tmTile struct {
+	entry *DirEntry
+	rect  tmRect
+}
+
+// center returns the tile's center in cell-aspect-corrected units.
+func (t tmTile) center() (float64, float64) {
+	return float64(t.rect.x) + float64(t.rect.w)/2,
+		(float64(t.rect.y) + float64(t.rect.h)/2) * cellAspect
+}
+
+// fRect is a rectangle in layout space.
+type fRect struct {
+	x, y, w, h float64
+}
+
+// layoutTreemap lays out entries (sorted by size, largest first) in a
+// w×h cell area. Entries without size or too small to occupy a cell are
+// omitted.
+func layoutTreemap(entries []*DirEntry, w, h int) []tmTile {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+
+	var items []*DirEntry
+	var total float64
+	for _, e := range entries {
+		if e.Size > 0 {
+			items = append(items, e)
+			total += float64(e.Size)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	bounds := fRect{w: float64(w), h: float64(h) * cellAspect}
+	scale := bounds.w * bounds.h / total
+	areas := make([]float64, len(items))
+	for i, e := range items {
+		areas[i] = float64(e.Size) * scale
+	}
+
+	rects := squarify(areas, bounds)
+
+	tiles := make([]tmTile, 0, len(items))
+	for i, r := range rects {
+		// Snap edges, not sizes, so neighbours share borders exactly.
+		x0, x1 := int(math.Round(r.x)), int(math.Round(r.x+r.w))
+		y0 := int(math.Round(r.y / cellAspect))
+		y1 := int(math.Round((r.y + r.h) / cellAspect))
+		if x1 <= x0 || y1 <= y0 {
+			continue
+		}
+		tiles = append(tiles, tmTile{
+			entry: items[i],
+			rect:  tmRect{x: x0, y: y0, w: x1 - x0, h: y1 - y0},
+		})
+	}
+	return tiles
+}
+
+// squarify lays out areas (sorted descending, summing to the area of r)
+// in r, returning one rectangle per area in the same order.
+func squarify(areas []float64, r fRect) []fRect {
+	out := make([]fRect, 0, len(areas))
+	var row []float64
+
+	for i := 0; i < len(areas); {
+		side := math.Min(r.w, r.h)
+		next := append(row, areas[i])
+		if len(row) == 0 || worstRatio(next, side) <= worstRatio(row, side) {
+			row = next
+			i++
+			continue
+		}
+		var placed []fRect
+		placed, r = layoutRow(row, r)
+		out = append(out, placed...)
+		row = nil
+	}
+	if len(row) > 0 {
+		placed, _ := layoutRow(row, r)
+		out = append(out, placed...)
+	}
+	return out
+}
+
+// worstRatio returns the worst aspect ratio of row laid out along a side
+// of the given length.
+func worstRatio(row []float64, side float64) float64 {
+	var sum, lo, hi float64
+	lo = math.Inf(1)
+	for _, a := range row {
+		sum += a
+		lo = math.Min(lo, a)
+		hi = math.Max(hi, a)
+	}
+	if sum == 0 || lo == 0 {
+		return math.Inf(1)
+	}
+	s2, side2 := sum*sum, side*side
+	return math.Max(side2*hi/s2, s2/(side2*lo))
+}
+
+// layoutRow places row along the shorter side of r and returns the placed
+// rectangles and the remaining free rectangle.
+func layoutRow(row []float64, r fRect) ([]fRect, fRect) {
+	var sum float64
+	for _, a := range row {
+		sum += a
+	}
+	placed := make([]fRect, len(row))
+
+	if r.w >= r.h {
+		// Column on the left.
+		colW := sum / r.h
+		y := r.y
+		for i, a := range row {
+			h := a / colW
+			placed[i] = fRect{x: r.x, y: y, w: colW, h: h}
+			y += h
+		}
+		return placed, fRect{x: r.x + colW, y: r.y, w: r.w - colW, h: r.h}
+	}
+
+	// Row along the top.
+	rowH := sum / r.w
+	x := r.x
+	for i, a := range row {
+		w := a / rowH
+		placed[i] = fRect{x: x, y: r.y, w: w, h: rowH}
+		x += w
+	}
+	return placed, fRect{x: r.x, y: r.y + rowH, w: r.w, h: r.h - rowH}
+}
+
+// ─── Spatial Navigation ──────────────────────────────────────────────────────
+
+// neighborTile returns the index of the tile nearest to tiles[from] in the
+// direction (dx, dy), one of the four unit directions, or from if there is
+// none. Tiles further off-axis are penalised so movement feels straight.
+func neighborTile(tiles []tmTile, from, dx, dy int) int {
+	if from < 0 || from >= len(tiles) {
+		return from
+	}
+	cx, cy := tiles[from].center()
+
+	best, bestScore := from, math.Inf(1)
+	for i, t := range tiles {
+		if i == from {
+			continue
+		}
+		tx, ty := t.center()
+		along := (tx-cx)*float64(dx) + (ty-cy)*float64(dy)
+		if along <= 0 {
+			continue
+		}
+		across := math.Abs((tx-cx)*float64(dy)) + math.Abs((ty-cy)*float64(dx))
+		if score := along + 2*across; score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,109 @@
+package analyze
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func sizedEntries(sizes ...int64) []*DirEntry {
+	out := make([]*DirEntry, len(sizes))
+	for i, s := range sizes {
+		out[i] = &DirEntry{Name: fmt.Sprintf("e%d", i), Size: s}
+	}
+	return out
+}
+
+func TestSquarify_AreasProportional(t *testing.T) {
+	areas := []float64{6, 6, 4, 3, 2, 2, 1} // the example from the paper
+	rects := squarify(areas, fRect{w: 6, h: 4})
+	if len(rects) != len(areas) {
+		t.Fatalf("got %d rects, want %d", len(rects), len(areas))
+	}
+	for i, r := range rects {
+		if got := r.w * r.h; math.Abs(got-areas[i]) > 1e-9 {
+			t.Errorf("rect %d area = %v, want %v", i, got, areas[i])
+		}
+		if r.x < -1e-9 || r.y < -1e-9 || r.x+r.w > 6+1e-9 || r.y+r.h > 4+1e-9 {
+			t.Errorf("rect %d %+v outside bounds", i, r)
+		}
+	}
+}
+
+func TestLayoutTreemap_TilesCoverWithoutOverlap(t *testing.T) {
+	const w, h = 80, 20
+	tiles := layoutTreemap(sizedEntries(500, 300, 120, 80, 40, 20, 10, 5, 0), w, h)
+	if len(tiles) == 0 {
+		t.Fatal("no tiles")
+	}
+
+	var grid [h][w]int
+	for i, tile := range tiles {
+		r := tile.rect
+		if r.x < 0 || r.y < 0 || r.x+r.w > w || r.y+r.h > h {
+			t.Fatalf("tile %d %+v outside %dx%d", i, r, w, h)
+		}
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				if grid[y][x] != 0 {
+					t.Fatalf("tiles %d and %d overlap at %d,%d", grid[y][x]-1, i, x, y)
+				}
+				grid[y][x] = i + 1
+			}
+		}
+		if tile.entry.Size == 0 {
+			t.Errorf("zero-size entry %s was laid out", tile.entry.Name)
+		}
+	}
+
+	// Snapping to shared edges leaves no holes.
+	for y := range grid {
+		for x := range grid[y] {
+			if grid[y][x] == 0 {
+				t.Fatalf("cell %d,%d not covered", x, y)
+			}
+		}
+	}
+
+	// The largest entry gets the largest tile.
+	first := tiles[0].rect.w * tiles[0].rect.h
+	for _, tile := range tiles[1:] {
+		if tile.rect.w*tile.rect.h > first {
+			t.Errorf("%s is larger than the biggest entry's tile", tile.entry.Name)
+		}
+	}
+}
+
+func TestLayoutTreemap_Empty(t *testing.T) {
+	if tiles := layoutTreemap(sizedEntries(0, 0), 40, 10); tiles != nil {
+		t.Errorf("zero-size entries: got %d tiles", len(tiles))
+	}
+	if tiles := layoutTreemap(sizedEntries(10), 0, 10); tiles != nil {
+		t.Errorf("zero width: got %d tiles", len(tiles))
+	}
+}
+
+func TestNeighborTile(t *testing.T) {
+	// A 2×2 grid of tiles:  0 1
+	//                       2 3
+	tiles := []tmTile{
+		{rect: tmRect{x: 0, y: 0, w: 10, h: 5}},
+		{rect: tmRect{x: 10, y: 0, w: 10, h: 5}},
+		{rect: tmRect{x: 0, y: 5, w: 10, h: 5}},
+		{rect: tmRect{x: 10, y: 5, w: 10, h: 5}},
+	}
+	for _, tc := range []struct {
+		from, dx, dy, want int
+	}{
+		{0, 1, 0, 1},
+		{0, 0, 1, 2},
+		{3, -1, 0, 2},
+		{3, 0, -1, 1},
+		{0, -1, 0, 0}, // nothing to the left: stay
+		{1, 0, -1, 1},
+	} {
+		if got := neighborTile(tiles, tc.from, tc.dx, tc.dy); got != tc.want {
+			t.Errorf("neighborTile(%d, %d,%d) = %d, want %d", tc.from, tc.dx, tc.dy, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package analyze
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+)
+
+// ─── Treemap Mode ────────────────────────────────────────────────────────────
+// The treemap replaces the list body with the current directory's children
+// as rectangles. Each top-level tile is filled with its type colour and
+// labelled; directory tiles show their own children as shaded rectangles
+// inside them. m.cursor still indexes visibleItems, so switching modes keeps
+// the selection.
+
+// kindColor returns the tile colour for an entry.
+func kindColor(e *DirEntry) lipgloss.AdaptiveColor {
+	if e.IsDir {
+		return clrDir
+	}
+	return fileKindColor(KindOf(e.Name))
+}
+
+// fileKindColor returns the colour used for a kind of file.
+func fileKindColor(kind FileKind) lipgloss.AdaptiveColor {
+	switch kind {
+	case KindVideo:
+		return ui.ColorSecondary
+	case KindAudio:
+		return ui.ColorAccent
+	case KindImage:
+		return ui.ColorViolet
+	case KindArchive:
+		return ui.ColorWarning
+	case KindDiskImage:
+		return ui.ColorError
+	case KindInstaller:
+		return ui.ColorHazy
+	case KindCode:
+		return ui.ColorTeal
+	case KindDocument:
+		return ui.ColorBlue
+	}
+	return ui.ColorMuted
+}
+
+// treemapSize returns the cell area available to the treemap.
+func (m AnalyzeModel) treemapSize() (int, int) {
+	w := m.width
+	if w < 40 {
+		w = 40
+	}
+	return w - 4, m.viewportHeight()
+}
+
+// treemapTiles lays out the visible items for the current terminal size.
+func (m AnalyzeModel) treemapTiles() []tmTile {
+	w, h := m.treemapSize()
+	return layoutTreemap(m.visibleItems(), w, h)
+}
+
+// selectedTile returns the index of the tile holding the cursor, falling
+// back to the first tile when the selected item is too small to be drawn.
+func (m AnalyzeModel) selectedTile(tiles []tmTile) int {
+	items := m.visibleItems()
+	if m.cursor >= 0 && m.cursor < len(items) {
+		for i, t := range tiles {
+			if t.entry == items[m.cursor] {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// selectEntry moves the cursor to e.
+func (m *AnalyzeModel) selectEntry(e *DirEntry) {
+	for i, item := range m.visibleItems() {
+		if item == e {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// updateTreemap handles keys while the treemap is shown.
+func (m AnalyzeModel) updateTreemap(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tiles := m.treemapTiles()
+	sel := m.selectedTile(tiles)
+	if len(tiles) > 0 {
+		// Act on the highlighted tile even if the cursor was on an
+		// entry too small to draw.
+		m.selectEntry(tiles[sel].entry)
+	}
+
+	move := func(dx, dy int) {
+		if len(tiles) > 0 {
+			m.selectEntry(tiles[neighborTile(tiles, sel, dx, dy)].entry)
+		}
+	}
+
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "t":
+		m.treemap = false
+		m.ensureVisible()
+
+	case "up", "k":
+		move(0, -1)
+	case "down", "j":
+		move(0, 1)
+	case "left", "h":
+		move(-1, 0)
+	case "right", "l":
+		move(1, 0)
+
+	case "enter":
+		if len(tiles) > 0 {
+			m.selectEntry(tiles[sel].entry)
+			m.drillInto()
+		}
+
+	case "backspace":
+		m.goBack()
+
+	default:
+		if !m.updateMarks(msg.String()) {
+			m.updateFilter(msg.String())
+		}
+	}
+
+	return m, nil
+}
+
+// ─── Treemap Rendering ───────────────────────────────────────────────────────
+
+// tmCell is one terminal cell of the treemap canvas.
+type tmCell struct {
+	ch   rune
+	fg   lipgloss.TerminalColor
+	bg   lipgloss.TerminalColor
+	bold bool
+}
+
+func (m AnalyzeModel) renderTreemap() string {
+	w, h := m.treemapSize()
+	tiles := m.treemapTiles()
+	if len(tiles) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(ui.ColorMuted).
+			Italic(true).
+			Render("  (nothing to draw)")
+	}
+	sel := m.selectedTile(tiles)
+
+	grid := make([][]tmCell, h)
+	for y := range grid {
+		grid[y] = make([]tmCell, w)
+		for x := range grid[y] {
+			grid[y][x] = tmCell{ch: ' '}
+		}
+	}
+
+	for i, t := range tiles {
+		bg := kindColor(t.entry)
+		if i == sel {
+			bg = clrCursor
+		}
+		r := t.rect
+
+		// Leave the right column and bottom row blank as a gutter
+		// between neighbours, when the tile is big enough to spare them.
+		iw, ih := r.w, r.h
+		if iw > 1 {
+			iw--
+		}
+		if ih > 1 {
+			ih--
+		}
+		for y := r.y; y < r.y+ih; y++ {
+			for x := r.x; x < r.x+iw; x++ {
+				grid[y][x] = tmCell{ch: ' ', bg: bg}
+			}
+		}
+
+		// Nested level: a directory's children as shaded blocks below
+		// the label.
+		if t.entry.IsDir && iw >= 4 && ih >= 3 {
+			for _, sub := range layoutTreemap(t.entry.Children, iw-2, ih-2) {
+				sr := sub.rect
+				for y := sr.y; y < sr.y+sr.h; y++ {
+					for x := sr.x; x < sr.x+sr.w; x++ {
+						ch := '▒'
+						if x == sr.x+sr.w-1 && sr.w > 1 || y == sr.y+sr.h-1 && sr.h > 1 {
+							ch = '░'
+						}
+						grid[r.y+1+y][r.x+1+x] = tmCell{ch: ch, fg: kindColor(sub.entry), bg: bg}
+					}
+				}
+			}
+		}
+
+		// Label: directories carry name and size on the first row, since
+		// their body shows the nested blocks; files put the size below.
+		fg := ui.ColorSurfaceDark
+		size := ui.FormatSize(t.entry.Size)
+		if m.pending(t.entry) {
+			size += " " + ui.IconPending
+		}
+		mark := " "
+		if m.marked[t.entry] {
+			mark = ui.IconCheck
+		}
+		switch {
+		case t.entry.IsDir:
+			writeLabel(grid[r.y][r.x:r.x+iw], mark+t.entry.Name+"  "+size, fg, bg)
+		case ih >= 2:
+			writeLabel(grid[r.y][r.x:r.x+iw], mark+t.entry.Name, fg, bg)
+			writeLabel(grid[r.y+1][r.x:r.x+iw], " "+size, fg, bg)
+		default:
+			writeLabel(grid[r.y][r.x:r.x+iw], mark+t.entry.Name, fg, bg)
+		}
+	}
+
+	lines := make([]string, h)
+	for y, row := range grid {
+		lines[y] = "  " + renderCells(row)
+	}
+
+	// Selected entry details, since small tiles have truncated labels.
+	if e := tiles[sel].entry; e != nil {
+		detail := lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(
+			"  " + ui.IconChevron + " " + e.Name + "  " + ui.FormatSize(e.Size))
+		lines = append(lines, detail)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeLabel writes text into row, truncating with an ellipsis.
+func writeLabel(row []tmCell, text string, fg, bg lipgloss.TerminalColor) {
+	runes := []rune(text)
+	if len(runes) > len(row) {
+		if len(row) < 2 {
+			return
+		}
+		runes = append(runes[:len(row)-1], '…')
+	}
+	for i, ch := range runes {
+		row[i] = tmCell{ch: ch, fg: fg, bg: bg, bold: true}
+	}
+}
+
+// renderCells renders a canvas row, styling runs of identical cells
+// together to keep the output small.
+func renderCells(row []tmCell) string {
+	var b strings.Builder
+	for start := 0; start < len(row); {
+		end := start + 1
+		for end < len(row) && sameStyle(row[end], row[start]) {
+			end++
+		}
+
+		var run strings.Builder
+		for _, c := range row[start:end] {
+			run.WriteRune(c.ch)
+		}
+		c := row[start]
+		style := lipgloss.NewStyle().Bold(c.bold)
+		if c.fg != nil {
+			style = style.Foreground(c.fg)
+		}
+		if c.bg != nil {
+			style = style.Background(c.bg)
+		}
+		b.WriteString(style.Render(run.String()))
+		start = end
+	}
+	return b.String()
+}
+
+func sameStyle(a, b tmCell) bool {
+	return a.fg == b.fg && a.bg == b.bg && a.bold == b.bold
+}
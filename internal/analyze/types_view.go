@@ -0,0 +1,222 @@
+package analyze
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakshaymaurya-felt/winmole/internal/ui"
+)
+
+// ─── Types Tab ───────────────────────────────────────────────────────────────
+// The Types tab shows the current directory's subtree broken down by file
+// kind (or extension), and drills from a row into the largest files of
+// that type anywhere below.
+
+// analyzeTab selects what the body shows.
+type analyzeTab int
+
+const (
+	tabTree analyzeTab = iota
+	tabTypes
+)
+
+// largestFilesLimit caps the drill-down list.
+const largestFilesLimit = 200
+
+// typesState is the Types tab's navigation state.
+type typesState struct {
+	breakdown  Breakdown
+	byExt      bool        // group by extension instead of kind
+	drilled    *TypeStat   // type whose files are listed, or nil
+	files      []*DirEntry // largest files of the drilled type
+	cursor     int
+	offset     int
+	statCursor int // cursor to restore when leaving the file list
+}
+
+// stats returns the rows of the breakdown list.
+func (t *typesState) stats() []TypeStat {
+	if t.byExt {
+		return t.breakdown.ByExt
+	}
+	return t.breakdown.ByKind
+}
+
+// rows returns the number of rows in the list being shown.
+func (t *typesState) rows() int {
+	if t.drilled != nil {
+		return len(t.files)
+	}
+	return len(t.stats())
+}
+
+// showTypes switches to the Types tab, recomputing the breakdown if the
+// current directory changed since it was last shown.
+func (m *AnalyzeModel) showTypes() {
+	m.tab = tabTypes
+	if m.types.breakdown.Root != m.current {
+		byExt := m.types.byExt
+		m.types = typesState{breakdown: BreakdownOf(m.current), byExt: byExt}
+	}
+}
+
+// updateTypes handles keys while the Types tab is shown.
+func (m AnalyzeModel) updateTypes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := &m.types
+
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "tab":
+		m.tab = tabTree
+
+	case "up", "k":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+
+	case "down", "j":
+		if t.cursor < t.rows()-1 {
+			t.cursor++
+		}
+
+	case "right", "l", "enter":
+		if t.drilled == nil {
+			stats := t.stats()
+			if t.cursor >= 0 && t.cursor < len(stats) {
+				stat := stats[t.cursor]
+				t.drilled = &stat
+				t.files = LargestFiles(m.current, MatchStat(stat, t.byExt), largestFilesLimit)
+				t.statCursor = t.cursor
+				t.cursor, t.offset = 0, 0
+			}
+		} else if msg.String() == "enter" && !m.readOnly && t.cursor < len(t.files) {
+			openInExplorer(t.files[t.cursor].Path)
+		}
+
+	case "left", "h", "backspace":
+		if t.drilled != nil {
+			t.drilled, t.files = nil, nil
+			t.cursor, t.offset = t.statCursor, 0
+		}
+
+	case "e":
+		if t.drilled == nil {
+			t.byExt = !t.byExt
+			t.cursor, t.offset = 0, 0
+		}
+	}
+
+	t.offset = scrollOffset(t.cursor, t.offset, m.viewportHeight())
+	return m, nil
+}
+
+// ─── Types Rendering ─────────────────────────────────────────────────────────
+
+func (m AnalyzeModel) renderTypes(w int) string {
+	t := m.types
+	total := t.breakdown.Bytes
+
+	empty := func(msg string) string {
+		return lipgloss.NewStyle().Foreground(ui.ColorMuted).Italic(true).Render("  " + msg)
+	}
+
+	barWidth := 20
+	if w > 110 {
+		barWidth = 30
+	}
+	vh := m.viewportHeight()
+	var lines []string
+
+	if t.drilled != nil {
+		title := lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(fmt.Sprintf(
+			"  Largest %s files  (%d files, %s)", t.drilled.Key, t.drilled.Count, ui.FormatSize(t.drilled.Bytes)))
+		lines = append(lines, title)
+		if len(t.files) == 0 {
+			return title + "\n" + empty("(no files)")
+		}
+		for i := t.offset; i < len(t.files) && i < t.offset+vh-1; i++ {
+			lines = append(lines, m.renderTypeFile(i, t.files[i], t.drilled.Bytes, barWidth))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	stats := t.stats()
+	if len(stats) == 0 {
+		return empty("(no files)")
+	}
+	for i := t.offset; i < len(stats) && i < t.offset+vh; i++ {
+		s := stats[i]
+		pct := 0.0
+		if total > 0 {
+			pct = float64(s.Bytes) / float64(total) * 100
+		}
+		label := s.Key
+		if label == "" {
+			label = "(no extension)"
+		}
+		name := lipgloss.NewStyle().Foreground(fileKindColor(s.Kind)).Bold(true).
+			Render(fmt.Sprintf("%-16s", label))
+		line := fmt.Sprintf("  %s %s  %s  %s %10s  %s",
+			lipgloss.NewStyle().Foreground(clrDim).Render(fmt.Sprintf("%3d.", i+1)),
+			ui.GradientBar(pct, barWidth),
+			lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(fmt.Sprintf("%5.1f%%", pct)),
+			name,
+			ui.FormatSize(s.Bytes),
+			lipgloss.NewStyle().Foreground(clrDim).Render(fmt.Sprintf("%d files", s.Count)))
+		lines = append(lines, withCursor(line, i == t.cursor))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m AnalyzeModel) renderTypeFile(i int, f *DirEntry, total int64, barWidth int) string {
+	pct := f.Percentage(total)
+
+	// Show the location relative to the directory being analyzed.
+	dir := filepath.Dir(f.Path)
+	if rel, err := filepath.Rel(m.current.Path, dir); err == nil {
+		dir = rel
+	}
+	maxName := m.width - barWidth - 40
+	if maxName < 12 {
+		maxName = 12
+	}
+	name := f.Name
+	if len(name) > maxName {
+		name = name[:maxName-1] + "…"
+	}
+
+	line := fmt.Sprintf("  %s %s  %s  %s  %s  %s",
+		lipgloss.NewStyle().Foreground(clrDim).Render(fmt.Sprintf("%3d.", i+1)),
+		ui.GradientBar(pct, barWidth),
+		lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(fmt.Sprintf("%5.1f%%", pct)),
+		lipgloss.NewStyle().Foreground(kindColor(f)).Render(name),
+		ui.FormatSize(f.Size),
+		lipgloss.NewStyle().Foreground(clrDim).Render(dir))
+	return withCursor(line, i == m.types.cursor)
+}
+
+// withCursor marks a rendered row as selected, as renderEntry does.
+func withCursor(line string, selected bool) string {
+	if !selected {
+		return line
+	}
+	cursor := lipgloss.NewStyle().Foreground(clrCursor).Bold(true).Render(ui.IconBlock)
+	return " " + cursor + line[2:]
+}
+
+// renderTabs renders the tab strip shown in the header.
+func (m AnalyzeModel) renderTabs() string {
+	tab := func(label string, active bool) string {
+		if active {
+			return lipgloss.NewStyle().Bold(true).Foreground(ui.ColorCoral).Underline(true).Render(label)
+		}
+		return lipgloss.NewStyle().Foreground(ui.ColorMuted).Render(label)
+	}
+	return "  " + tab("Tree", m.tab == tabTree) + "  " + tab("Types", m.tab == tabTypes)
+}
@@ -64,7 +64,11 @@ func (m AnalyzeModel) renderHeader(w int) string {
 		Foreground(ui.ColorMuted).
 		Render("  " + strings.Join(crumbs, " "+ui.IconChevron+" "))
 
-	inner := lipgloss.JoinVertical(lipgloss.Left, title, pathLine, bcStr)
+	tabs := m.renderTabs()
+	if m.live != nil {
+		tabs += "  " + m.scanningLine()
+	}
+	inner := lipgloss.JoinVertical(lipgloss.Left, title, pathLine, bcStr, tabs)
 
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -76,12 +80,31 @@ func (m AnalyzeModel) renderHeader(w int) string {
 // ─── Body (file list) ────────────────────────────────────────────────────────
 
 func (m AnalyzeModel) renderBody(w int) string {
+	if m.batch != nil {
+		return m.renderBatch(w)
+	}
+	if m.search.active {
+		return m.renderSearch(w)
+	}
+	if m.tab == tabTypes {
+		return m.renderTypes(w)
+	}
 	items := m.visibleItems()
+	if m.treemap && len(items) > 0 {
+		return m.renderTreemap()
+	}
 	if len(items) == 0 {
+		msg := "  (empty directory)"
+		if m.pending(m.current) {
+			msg = "  (scanning…)"
+		}
+		if m.filter.Active() && len(m.current.Children) > 0 {
+			msg = "  (nothing matches the filter — F clears it)"
+		}
 		return lipgloss.NewStyle().
 			Foreground(ui.ColorMuted).
 			Italic(true).
-			Render("  (empty directory)")
+			Render(msg)
 	}
 
 	vh := m.viewportHeight()
@@ -123,6 +146,12 @@ func (m AnalyzeModel) renderEntry(num int, entry *DirEntry, parentSize int64, ba
 	if entry.IsDir {
 		icon = ui.IconFolder
 	}
+	if m.marked[entry] {
+		icon = lipgloss.NewStyle().Foreground(ui.ColorSuccess).Bold(true).Render(ui.IconCheck)
+		if !entry.IsDir {
+			icon += " "
+		}
+	}
 
 	// ── Name ─────────────────────────────────────────────────
 	nameColor := clrFile
@@ -150,6 +179,9 @@ func (m AnalyzeModel) renderEntry(num int, entry *DirEntry, parentSize int64, ba
 	numStr := lipgloss.NewStyle().Foreground(clrDim).Render(fmt.Sprintf("%3d.", num))
 	pctStr := lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(fmt.Sprintf("%5.1f%%", pct))
 	sizeStr := ui.FormatSize(entry.Size)
+	if m.pending(entry) {
+		sizeStr += " " + pendingTag()
+	}
 
 	age := "     "
 	if entry.IsOld() {
@@ -163,12 +195,6 @@ func (m AnalyzeModel) renderEntry(num int, entry *DirEntry, parentSize int64, ba
 	if selected {
 		cursor := lipgloss.NewStyle().Foreground(clrCursor).Bold(true).Render(ui.IconBlock)
 		line = " " + cursor + line[2:]
-		if m.confirmDelete {
-			line += lipgloss.NewStyle().
-				Foreground(ui.ColorError).
-				Bold(true).
-				Render("  " + ui.IconWarning + " Press Enter to delete")
-		}
 	}
 
 	return line
@@ -187,22 +213,64 @@ func (m AnalyzeModel) renderFooter(w int) string {
 				Render("  "+ui.IconError+" "+m.err.Error()))
 	}
 
-	// Filter indicator.
-	if m.largeOnly {
+	// Outcome of the last batch.
+	if m.status != "" && m.batch == nil {
+		parts = append(parts, ui.SuccessStyle().Render("  "+ui.IconSuccess+" "+m.status))
+	}
+
+	// Filter and mark indicators.
+	var tags []string
+	if m.filter.Active() && m.tab == tabTree && !m.search.active {
+		tags = append(tags, m.filterTag())
+	}
+	if len(m.marked) > 0 {
+		tags = append(tags, m.markTag())
+	}
+	if len(tags) > 0 {
+		parts = append(parts, "  "+strings.Join(tags, " "))
+	}
+	if m.readOnly {
 		parts = append(parts,
-			"  "+ui.TagWarningStyle().Render(" >100 MiB filter "))
+			"  "+ui.TagWarningStyle().Render(" imported scan (read-only) "))
 	}
 
 	// Keybindings.
-	hints := []string{
-		"↑↓ nav",
-		"→ drill",
-		"← back",
-		"Enter open",
-		"⌫ delete",
-		"L large",
-		"q quit",
+	var hints []string
+	switch {
+	case m.batch != nil && m.batch.plan.Action == BatchDelete:
+		hints = []string{"Enter delete", "Esc cancel"}
+	case m.batch != nil:
+		hints = []string{"type a path", "Enter " + strings.ToLower(m.batch.plan.Action.String()), "Esc cancel"}
+	case m.search.active:
+		hints = []string{"type to search", "↑↓ nav", "Enter jump", "Esc cancel"}
+	case m.tab == tabTypes && m.types.drilled != nil:
+		hints = []string{"↑↓ nav", "← types"}
+		if !m.readOnly {
+			hints = append(hints, "Enter open")
+		}
+		hints = append(hints, "Tab tree")
+	case m.tab == tabTypes:
+		group := "e by extension"
+		if m.types.byExt {
+			group = "e by type"
+		}
+		hints = []string{"↑↓ nav", "→ largest files", group, "Tab tree"}
+	case m.treemap:
+		hints = []string{"↑↓←→ move", "Enter drill", "⌫ back", "Space mark", "d/m/x del/move/export",
+			"t list", "/ search", "s/o/y filter", "Tab types"}
+	default:
+		hints = []string{"↑↓ nav", "→ drill", "← back"}
+		if m.readOnly {
+			hints = append(hints, "Space mark", "x export")
+		} else {
+			hints = append(hints, "Enter open", "Space mark", "d/m/x del/move/export")
+		}
+		hints = append(hints, "t treemap", "/ search", "s/o/y filter", "Tab types")
+	}
+	if m.filter.Active() && m.tab == tabTree && !m.search.active && m.batch == nil {
+		hints = append(hints, "F clear")
 	}
+	hints = append(hints, "q quit")
 	hintStr := strings.Join(hints, " "+ui.IconPipe+" ")
 	parts = append(parts, ui.HintBarStyle().Render("  "+hintStr))
 
@@ -0,0 +1,138 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Electron App Caches ─────────────────────────────────────────────────────
+// Electron apps (Teams, Slack, Discord, Spotify, Postman, VS Code forks…)
+// embed Chromium and keep the same disposable cache folders as a browser
+// profile. Rather than hard-coding each app, data directories are
+// discovered by that cache layout.
+
+// ElectronApp is a discovered Electron (or CEF) app data directory.
+type ElectronApp struct {
+	// Name is the display name derived from the directory path.
+	Name string
+
+	// Dir is the app's data directory.
+	Dir string
+}
+
+// electronMarkers are the cache folders that identify a Chromium-style app
+// data directory. A directory must contain at least two of them.
+var electronMarkers = []string{"Cache", "Code Cache", "GPUCache"}
+
+// electronCacheSubdirs are the cache folders cleaned within an app's data
+// directory.
+var electronCacheSubdirs = []string{
+	"Cache",
+	"Code Cache",
+	"GPUCache",
+	"DawnCache",
+	"GrShaderCache",
+	filepath.Join("Service Worker", "CacheStorage"),
+	filepath.Join("Service Worker", "ScriptCache"),
+}
+
+// electronSearchDepth is how many levels below %APPDATA% and
+// %LOCALAPPDATA% are searched (e.g. "Slack" and "Microsoft\Teams").
+const electronSearchDepth = 2
+
+// DiscoverElectronApps finds Electron app data directories under
+// %APPDATA% and %LOCALAPPDATA%. Browsers (scanned by ScanBrowserCaches)
+// and VS Code's own directory (scanned by ScanDevCaches) are excluded.
+func DiscoverElectronApps() []ElectronApp {
+	local := os.Getenv("LOCALAPPDATA")
+	roaming := os.Getenv("APPDATA")
+
+	exclude := []string{
+		filepath.Join(roaming, "Code"),
+		filepath.Join(local, "Packages"), // Store apps: see ScanStoreAppCaches.
+	}
+	for _, b := range chromiumBrowsers(local, roaming) {
+		exclude = append(exclude, b.flatDirs...)
+	}
+
+	return discoverElectronApps([]string{roaming, local}, exclude)
+}
+
+// discoverElectronApps searches each root up to electronSearchDepth levels
+// deep for directories with a Chromium cache layout, skipping exclude.
+// Matched directories are not searched further.
+func discoverElectronApps(roots, exclude []string) []ElectronApp {
+	excluded := make(map[string]bool, len(exclude))
+	for _, dir := range exclude {
+		excluded[strings.ToLower(filepath.Clean(dir))] = true
+	}
+
+	var apps []ElectronApp
+	var walk func(root, dir string, depth int)
+	walk = func(root, dir string, depth int) {
+		for _, name := range listSubdirs(dir) {
+			path := filepath.Join(dir, name)
+			if excluded[strings.ToLower(path)] {
+				continue
+			}
+			if isElectronDataDir(path) {
+				rel, _ := filepath.Rel(root, path)
+				apps = append(apps, ElectronApp{
+					Name: strings.ReplaceAll(rel, string(filepath.Separator), " "),
+					Dir:  path,
+				})
+				continue
+			}
+			if depth < electronSearchDepth {
+				walk(root, path, depth+1)
+			}
+		}
+	}
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		walk(root, root, 1)
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+	return apps
+}
+
+// isElectronDataDir reports whether dir contains at least two of the
+// Chromium cache marker folders.
+func isElectronDataDir(dir string) bool {
+	found := 0
+	for _, marker := range electronMarkers {
+		if info, err := os.Stat(filepath.Join(dir, marker)); err == nil && info.IsDir() {
+			found++
+		}
+	}
+	return found >= 2
+}
+
+// ScanAppCaches scans the cache folders of every discovered Electron app.
+// Items use the "apps" category and one description per app.
+func ScanAppCaches(wl *whitelist.Whitelist) []CleanItem {
+	var items []CleanItem
+	for _, app := range DiscoverElectronApps() {
+		if wl != nil && wl.IsWhitelisted(app.Dir) {
+			continue
+		}
+		desc := app.Name + " cache"
+		for _, subdir := range electronCacheSubdirs {
+			cacheDir := filepath.Join(app.Dir, subdir)
+			if _, err := os.Stat(cacheDir); err != nil {
+				continue
+			}
+			items = append(items, scanDirectory(cacheDir, "apps", desc, wl)...)
+		}
+	}
+	return items
+}
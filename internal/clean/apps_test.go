@@ -0,0 +1,36 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverElectronApps(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join("Slack", "Cache"),
+		filepath.Join("Slack", "Code Cache"),
+		filepath.Join("Microsoft", "Teams", "Cache"),
+		filepath.Join("Microsoft", "Teams", "GPUCache"),
+		filepath.Join("Opera Software", "Opera Stable", "Cache"),
+		filepath.Join("Opera Software", "Opera Stable", "GPUCache"),
+		filepath.Join("SomeTool", "Cache"), // Only one marker.
+		filepath.Join("A", "B", "C", "Cache"),
+		filepath.Join("A", "B", "C", "GPUCache"), // Too deep.
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exclude := []string{filepath.Join(root, "Opera Software", "Opera Stable")}
+	apps := discoverElectronApps([]string{root}, exclude)
+
+	if len(apps) != 2 {
+		t.Fatalf("got %+v, want Microsoft Teams and Slack", apps)
+	}
+	if apps[0].Name != "Microsoft Teams" || apps[1].Name != "Slack" {
+		t.Errorf("unexpected names: %q, %q", apps[0].Name, apps[1].Name)
+	}
+}
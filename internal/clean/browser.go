@@ -5,144 +5,84 @@ import (
 	"path/filepath"
 	"strings"
 
+	"golang.org/x/sys/windows/registry"
+
 	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
 )
 
-// ─── Browser Definitions ─────────────────────────────────────────────────────
-
-// browserDef describes a Chromium-based browser's cache locations.
-type browserDef struct {
-	name    string   // Human-readable browser name.
-	base    string   // Base "User Data" directory.
-	subdirs []string // Cache subdirectories within each profile.
-}
-
 // ─── Browser Cache Scanning ──────────────────────────────────────────────────
 
 // ScanBrowserCaches auto-detects installed browsers and scans their cache
-// directories across ALL profiles (Default, Profile 1, Profile 2, …).
+// directories across ALL profiles, as listed in each Chromium browser's
+// Local State and each Gecko browser's profiles.ini.
+//
+// Items of browsers that procs reports as running are marked InUse so the
+// caller can skip them or close the browser first. procs may be nil to
+// skip process detection.
 //
 // Only cache directories are touched — bookmarks, passwords, cookies,
 // history, extensions, and settings are NEVER included.
-func ScanBrowserCaches(wl *whitelist.Whitelist) []CleanItem {
+func ScanBrowserCaches(wl *whitelist.Whitelist, procs ProcessLister) []CleanItem {
 	local := os.Getenv("LOCALAPPDATA")
-
-	browsers := []browserDef{
-		{
-			name: "Chrome",
-			base: filepath.Join(local, "Google", "Chrome", "User Data"),
-			subdirs: []string{
-				"Cache",
-				"Code Cache",
-				"GPUCache",
-				filepath.Join("Service Worker", "CacheStorage"),
-			},
-		},
-		{
-			name: "Edge",
-			base: filepath.Join(local, "Microsoft", "Edge", "User Data"),
-			subdirs: []string{
-				"Cache",
-				"Code Cache",
-				"GPUCache",
-				filepath.Join("Service Worker", "CacheStorage"),
-			},
-		},
-		{
-			name: "Brave",
-			base: filepath.Join(local, "BraveSoftware", "Brave-Browser", "User Data"),
-			subdirs: []string{
-				"Cache",
-				"Code Cache",
-				"GPUCache",
-			},
-		},
-	}
+	roaming := os.Getenv("APPDATA")
 
 	var items []CleanItem
 
 	// Scan Chromium-based browsers.
-	for _, b := range browsers {
-		if _, err := os.Stat(b.base); err != nil {
-			continue // Browser not installed.
-		}
-
-		profiles := discoverChromiumProfiles(b.base)
+	for _, b := range chromiumBrowsers(local, roaming) {
+		profiles := b.profiles()
 		for _, profile := range profiles {
+			desc := b.name + " cache"
+			if len(profiles) > 1 && profile.name != "" {
+				desc += " (" + profile.name + ")"
+			}
 			for _, subdir := range b.subdirs {
-				cacheDir := filepath.Join(profile, subdir)
+				cacheDir := filepath.Join(profile.dir, subdir)
 				if _, err := os.Stat(cacheDir); err != nil {
 					continue
 				}
-				desc := b.name + " cache"
-				dirItems := scanDirectory(cacheDir, "browser", desc, wl)
-				items = append(items, dirItems...)
+				for _, item := range scanDirectory(cacheDir, "browser", desc, wl) {
+					item.Browser = b.name
+					items = append(items, item)
+				}
 			}
 		}
 	}
 
-	// Firefox uses a different profile structure.
-	firefoxItems := scanFirefoxCaches(local, wl)
-	items = append(items, firefoxItems...)
-
-	return items
-}
-
-// ─── Profile Discovery ───────────────────────────────────────────────────────
+	// Firefox and its forks use a different profile structure.
+	geckoItems := scanGeckoCaches(roaming, local, wl)
+	items = append(items, geckoItems...)
 
-// discoverChromiumProfiles returns all profile directories within a
-// Chromium-based browser's User Data directory. Profiles are named
-// "Default", "Profile 1", "Profile 2", etc.
-func discoverChromiumProfiles(userDataDir string) []string {
-	entries, err := os.ReadDir(userDataDir)
-	if err != nil {
-		return nil
+	if procs != nil {
+		markRunning(items, procs)
 	}
 
-	var profiles []string
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		// Chromium profiles: "Default", "Profile 1", "Profile 2", …
-		// Also "Guest Profile", "System Profile" may exist.
-		if name == "Default" || strings.HasPrefix(name, "Profile ") {
-			profiles = append(profiles, filepath.Join(userDataDir, name))
-		}
-	}
-
-	return profiles
+	return items
 }
 
-// ─── Firefox ─────────────────────────────────────────────────────────────────
-
-// scanFirefoxCaches scans Firefox cache2 directories across all profiles.
-// Only the cache2 directory is scanned — profile data (bookmarks,
-// passwords, extensions) is never touched.
-func scanFirefoxCaches(local string, wl *whitelist.Whitelist) []CleanItem {
-	profilesDir := filepath.Join(local, "Mozilla", "Firefox", "Profiles")
-	if _, err := os.Stat(profilesDir); err != nil {
-		return nil
-	}
-
-	var items []CleanItem
-
-	profiles, _ := filepath.Glob(filepath.Join(profilesDir, "*"))
-	for _, profile := range profiles {
-		info, err := os.Stat(profile)
-		if err != nil || !info.IsDir() {
+// registeredAppPaths returns the executable paths registered for image
+// under App Paths, per-user first, then machine-wide.
+func registeredAppPaths(image string) []string {
+	var paths []string
+	for _, root := range []registry.Key{registry.CURRENT_USER, registry.LOCAL_MACHINE} {
+		key, err := registry.OpenKey(root,
+			`SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\`+image, registry.QUERY_VALUE)
+		if err != nil {
 			continue
 		}
-
-		cacheDir := filepath.Join(profile, "cache2")
-		if _, err := os.Stat(cacheDir); err != nil {
+		path, valType, valErr := key.GetStringValue("")
+		key.Close()
+		if valErr != nil {
 			continue
 		}
-
-		dirItems := scanDirectory(cacheDir, "browser", "Firefox cache", wl)
-		items = append(items, dirItems...)
+		if valType == registry.EXPAND_SZ {
+			if expanded, expErr := registry.ExpandString(path); expErr == nil {
+				path = expanded
+			}
+		}
+		if path = strings.Trim(path, `"`); path != "" {
+			paths = append(paths, path)
+		}
 	}
-
-	return items
+	return paths
 }
@@ -0,0 +1,191 @@
+package clean
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ─── Chromium Browser Registry ───────────────────────────────────────────────
+
+// chromiumBrowser describes a Chromium-based browser's cache locations.
+type chromiumBrowser struct {
+	name  string // Human-readable browser name.
+	image string // Process image name (e.g. "chrome.exe").
+
+	// userData is the "User Data" directory holding Local State and one
+	// directory per profile. It may contain glob patterns (e.g. for
+	// packaged browsers whose folder name includes a publisher hash).
+	userData string
+
+	// flatDirs, when set, replaces userData for browsers that keep a single
+	// profile directly in their data directories (Opera).
+	flatDirs []string
+
+	subdirs []string // Cache subdirectories within each profile.
+}
+
+// chromiumCacheSubdirs are the cache directories common to Chromium profiles.
+var chromiumCacheSubdirs = []string{
+	"Cache",
+	"Code Cache",
+	"GPUCache",
+	filepath.Join("Service Worker", "CacheStorage"),
+}
+
+// chromiumBrowsers returns the Chromium-based browsers WinMole knows
+// about, rooted at the given %LOCALAPPDATA% and %APPDATA% directories.
+func chromiumBrowsers(local, roaming string) []chromiumBrowser {
+	userData := func(parts ...string) string {
+		return filepath.Join(append([]string{local}, append(parts, "User Data")...)...)
+	}
+	opera := func(dir string) []string {
+		return []string{
+			filepath.Join(local, "Opera Software", dir),
+			filepath.Join(roaming, "Opera Software", dir),
+		}
+	}
+
+	browsers := []chromiumBrowser{
+		{name: "Chrome", image: "chrome.exe", userData: userData("Google", "Chrome")},
+		{name: "Chrome Beta", image: "chrome.exe", userData: userData("Google", "Chrome Beta")},
+		{name: "Chrome Dev", image: "chrome.exe", userData: userData("Google", "Chrome Dev")},
+		{name: "Chrome Canary", image: "chrome.exe", userData: userData("Google", "Chrome SxS")},
+		{name: "Chromium", image: "chrome.exe", userData: userData("Chromium")},
+		{name: "Edge", image: "msedge.exe", userData: userData("Microsoft", "Edge")},
+		{name: "Edge Beta", image: "msedge.exe", userData: userData("Microsoft", "Edge Beta")},
+		{name: "Edge Dev", image: "msedge.exe", userData: userData("Microsoft", "Edge Dev")},
+		{name: "Edge Canary", image: "msedge.exe", userData: userData("Microsoft", "Edge SxS")},
+		{name: "Brave", image: "brave.exe", userData: userData("BraveSoftware", "Brave-Browser")},
+		{name: "Vivaldi", image: "vivaldi.exe", userData: userData("Vivaldi")},
+		{name: "Yandex", image: "browser.exe", userData: userData("Yandex", "YandexBrowser")},
+		{name: "Thorium", image: "thorium.exe", userData: userData("Thorium")},
+		{name: "Arc", image: "Arc.exe", userData: userData("Packages", "TheBrowserCompany.Arc_*", "LocalCache", "Local", "Arc")},
+		{name: "Opera", image: "opera.exe", flatDirs: opera("Opera Stable")},
+		{name: "Opera GX", image: "opera.exe", flatDirs: opera("Opera GX Stable")},
+	}
+	for i := range browsers {
+		browsers[i].subdirs = chromiumCacheSubdirs
+	}
+	return browsers
+}
+
+// ─── Profile Discovery ───────────────────────────────────────────────────────
+
+// chromiumProfile is a single browser profile.
+type chromiumProfile struct {
+	dir  string // Profile directory.
+	name string // Display name shown in the browser's profile menu.
+}
+
+// profiles returns the installed profiles of b, or nil if b is not installed.
+func (b chromiumBrowser) profiles() []chromiumProfile {
+	if len(b.flatDirs) > 0 {
+		var profiles []chromiumProfile
+		for _, dir := range b.flatDirs {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				profiles = append(profiles, chromiumProfile{dir: dir})
+			}
+		}
+		return profiles
+	}
+
+	roots := []string{b.userData}
+	if strings.ContainsAny(b.userData, "*?[") {
+		roots, _ = filepath.Glob(b.userData)
+	}
+
+	var profiles []chromiumProfile
+	for _, root := range roots {
+		if _, err := os.Stat(root); err != nil {
+			continue // Browser not installed.
+		}
+		profiles = append(profiles, discoverChromiumProfiles(root)...)
+	}
+	return profiles
+}
+
+// chromiumSpecialProfiles are profile directories that never appear in
+// Local State's info_cache but hold caches of their own.
+var chromiumSpecialProfiles = []chromiumProfile{
+	{dir: "Guest Profile", name: "Guest"},
+	{dir: "System Profile", name: "System"},
+}
+
+// discoverChromiumProfiles returns all profile directories within a
+// Chromium-based browser's User Data directory. Profiles and their display
+// names come from Local State's profile.info_cache, so renamed profile
+// folders are found; the Guest and System profiles are added explicitly.
+// Without a readable Local State, folders named "Default" and "Profile N"
+// are used.
+func discoverChromiumProfiles(userDataDir string) []chromiumProfile {
+	names, ok := readChromiumLocalState(filepath.Join(userDataDir, "Local State"))
+	if !ok {
+		names = make(map[string]string)
+		entries, _ := os.ReadDir(userDataDir)
+		for _, e := range entries {
+			if e.IsDir() && (e.Name() == "Default" || strings.HasPrefix(e.Name(), "Profile ")) {
+				names[e.Name()] = e.Name()
+			}
+		}
+	}
+
+	var profiles []chromiumProfile
+	for dir, name := range names {
+		path := filepath.Join(userDataDir, dir)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue // Listed but deleted.
+		}
+		if name == "" {
+			name = dir
+		}
+		profiles = append(profiles, chromiumProfile{dir: path, name: name})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].dir < profiles[j].dir
+	})
+
+	for _, special := range chromiumSpecialProfiles {
+		path := filepath.Join(userDataDir, special.dir)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			profiles = append(profiles, chromiumProfile{dir: path, name: special.name})
+		}
+	}
+
+	return profiles
+}
+
+// chromiumLocalState is the subset of the Local State file WinMole reads.
+type chromiumLocalState struct {
+	Profile struct {
+		InfoCache map[string]struct {
+			Name string `json:"name"`
+		} `json:"info_cache"`
+	} `json:"profile"`
+}
+
+// readChromiumLocalState maps profile directory names to display names
+// from a Local State file. ok is false if the file is missing, unreadable
+// or lists no profiles.
+func readChromiumLocalState(path string) (names map[string]string, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var state chromiumLocalState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, false
+	}
+	if len(state.Profile.InfoCache) == 0 {
+		return nil, false
+	}
+
+	names = make(map[string]string, len(state.Profile.InfoCache))
+	for dir, info := range state.Profile.InfoCache {
+		names[dir] = info.Name
+	}
+	return names, true
+}
@@ -0,0 +1,57 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverChromiumProfiles(t *testing.T) {
+	userData := t.TempDir()
+	for _, dir := range []string{"Default", "Work Profile", "Guest Profile", "System Profile", "Crashpad"} {
+		if err := os.MkdirAll(filepath.Join(userData, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := `{"profile":{"info_cache":{
+		"Default":{"name":"Personal"},
+		"Work Profile":{"name":"Work"},
+		"Profile 9":{"name":"Deleted"}}}}`
+	if err := os.WriteFile(filepath.Join(userData, "Local State"), []byte(state), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles := discoverChromiumProfiles(userData)
+
+	want := []chromiumProfile{
+		{dir: filepath.Join(userData, "Default"), name: "Personal"},
+		{dir: filepath.Join(userData, "Work Profile"), name: "Work"},
+		{dir: filepath.Join(userData, "Guest Profile"), name: "Guest"},
+		{dir: filepath.Join(userData, "System Profile"), name: "System"},
+	}
+	if len(profiles) != len(want) {
+		t.Fatalf("got %+v, want %+v", profiles, want)
+	}
+	for i := range want {
+		if profiles[i] != want[i] {
+			t.Errorf("profile %d = %+v, want %+v", i, profiles[i], want[i])
+		}
+	}
+}
+
+func TestDiscoverChromiumProfilesWithoutLocalState(t *testing.T) {
+	userData := t.TempDir()
+	for _, dir := range []string{"Default", "Profile 2", "Crashpad"} {
+		if err := os.MkdirAll(filepath.Join(userData, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	profiles := discoverChromiumProfiles(userData)
+	if len(profiles) != 2 {
+		t.Fatalf("got %+v, want Default and Profile 2", profiles)
+	}
+	if profiles[0].name != "Default" || profiles[1].name != "Profile 2" {
+		t.Errorf("unexpected names: %+v", profiles)
+	}
+}
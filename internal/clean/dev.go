@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
 	"github.com/lakshaymaurya-felt/winmole/internal/core"
 	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
 )
@@ -18,21 +19,81 @@ type devCacheDef struct {
 	name        string
 	paths       []string
 	description string
+	riskLevel   string
 }
 
 // ─── Developer Cache Scanning ────────────────────────────────────────────────
 
 // ScanDevCaches scans developer tool caches (npm, pip, Cargo, Gradle,
-// NuGet, VS Code, JetBrains) and returns discovered items.
+// NuGet, Maven, pnpm, Yarn, conda, Bun, Deno, ccache/sccache, VS Code,
+// JetBrains) and returns discovered items. For versioned layouts (Gradle
+// wrapper distributions, VS Code extensions, JetBrains IDE dirs, rustup
+// toolchains) only versions older than the newest keepVersions per
+// product are included.
 //
 // SAFETY: .cargo\bin is NEVER scanned — only registry\cache and
 // registry\src are included for Cargo.
-func ScanDevCaches(wl *whitelist.Whitelist) []CleanItem {
+func ScanDevCaches(wl *whitelist.Whitelist, keepVersions int) []CleanItem {
+	home := os.Getenv("USERPROFILE")
+	local := os.Getenv("LOCALAPPDATA")
+
+	var items []CleanItem
+
+	for _, c := range devCaches() {
+		for _, p := range c.paths {
+			if _, err := os.Stat(p); err != nil {
+				continue
+			}
+			if wl != nil && wl.IsWhitelisted(p) {
+				continue
+			}
+			dirItems := scanDirectory(p, "dev", c.description, wl)
+			items = append(items, withRisk(dirItems, c.riskLevel)...)
+		}
+	}
+
+	// Versioned layouts: keep the newest versions of each product.
+	items = append(items, scanGradleDists(home, keepVersions, wl)...)
+	items = append(items, scanVSCodeExtensions(home, keepVersions, wl)...)
+	items = append(items, withRisk(scanRustupToolchains(home, keepVersions, wl), config.RiskMedium)...)
+
+	// JetBrains: caches of current IDE versions, whole dirs of old ones.
+	jetbrainsItems := scanJetBrainsCaches(local, keepVersions, wl)
+	items = append(items, jetbrainsItems...)
+
+	return items
+}
+
+// devCachePaths returns the directories of the developer cache with the
+// given description, e.g. for sizing a native pruner.
+func devCachePaths(description string) []string {
+	for _, c := range devCaches() {
+		if c.description == description {
+			return c.paths
+		}
+	}
+	return nil
+}
+
+// devCacheTargets are config targets scanned as developer caches. Their
+// paths, description and risk are defined once, in config.GetCleanTargets.
+var devCacheTargets = []string{
+	"MavenRepository",
+	"PnpmStore",
+	"YarnCache",
+	"CondaPkgs",
+	"BunCache",
+	"DenoCache",
+	"CompilerCache",
+}
+
+// devCaches returns the developer cache locations of the current user.
+func devCaches() []devCacheDef {
 	home := os.Getenv("USERPROFILE")
 	local := os.Getenv("LOCALAPPDATA")
 	roaming := os.Getenv("APPDATA")
 
-	caches := []devCacheDef{
+	defs := []devCacheDef{
 		{
 			name:        "npm",
 			paths:       []string{filepath.Join(roaming, "npm-cache")},
@@ -63,6 +124,7 @@ func ScanDevCaches(wl *whitelist.Whitelist) []CleanItem {
 			name:        "NuGet",
 			paths:       []string{filepath.Join(home, ".nuget", "packages")},
 			description: "NuGet package cache",
+			riskLevel:   config.RiskMedium,
 		},
 		{
 			name: "VS Code",
@@ -74,50 +136,45 @@ func ScanDevCaches(wl *whitelist.Whitelist) []CleanItem {
 		},
 	}
 
-	var items []CleanItem
-
-	for _, c := range caches {
-		for _, p := range c.paths {
-			if _, err := os.Stat(p); err != nil {
-				continue
-			}
-			if wl != nil && wl.IsWhitelisted(p) {
-				continue
-			}
-			dirItems := scanDirectory(p, "dev", c.description, wl)
-			items = append(items, dirItems...)
+	for _, name := range devCacheTargets {
+		if t, ok := config.GetTarget(name); ok {
+			defs = append(defs, devCacheDef{
+				name:        t.Name,
+				paths:       t.Paths,
+				description: t.Description,
+				riskLevel:   t.RiskLevel,
+			})
 		}
 	}
-
-	// JetBrains: only scan caches subdirectories within each IDE.
-	jetbrainsItems := scanJetBrainsCaches(local, wl)
-	items = append(items, jetbrainsItems...)
-
-	return items
+	return defs
 }
 
 // ─── JetBrains Cache Scanning ────────────────────────────────────────────────
 
-// scanJetBrainsCaches scans the "caches" directory within each JetBrains
-// IDE installation directory, avoiding settings and other IDE data.
-func scanJetBrainsCaches(local string, wl *whitelist.Whitelist) []CleanItem {
+// scanJetBrainsCaches scans the "caches" directory within the newest
+// keepVersions directories of each JetBrains IDE, avoiding settings and
+// other IDE data. Local data of older IDE versions is offered as a whole
+// unless whitelisted, in which case only its caches are scanned.
+func scanJetBrainsCaches(local string, keepVersions int, wl *whitelist.Whitelist) []CleanItem {
 	jetbrainsDir := filepath.Join(local, "JetBrains")
 	if _, err := os.Stat(jetbrainsDir); err != nil {
 		return nil
 	}
 
-	entries, err := os.ReadDir(jetbrainsDir)
-	if err != nil {
-		return nil
-	}
+	current, old := jetbrainsVersions(jetbrainsDir, keepVersions)
 
-	var items []CleanItem
-	for _, e := range entries {
-		if !e.IsDir() {
+	var removable []versionedDir
+	for _, d := range old {
+		if wl != nil && wl.IsWhitelisted(d.path) {
+			current = append(current, filepath.Base(d.path))
 			continue
 		}
+		removable = append(removable, d)
+	}
 
-		cachesDir := filepath.Join(jetbrainsDir, e.Name(), "caches")
+	var items []CleanItem
+	for _, name := range current {
+		cachesDir := filepath.Join(jetbrainsDir, name, "caches")
 		if _, err := os.Stat(cachesDir); err != nil {
 			continue
 		}
@@ -126,11 +183,14 @@ func scanJetBrainsCaches(local string, wl *whitelist.Whitelist) []CleanItem {
 			continue
 		}
 
-		desc := "JetBrains " + e.Name() + " cache"
+		desc := "JetBrains " + name + " cache"
 		dirItems := scanDirectory(cachesDir, "dev", desc, wl)
-		items = append(items, dirItems...)
+		items = append(items, withRisk(dirItems, config.RiskMedium)...)
 	}
 
+	oldItems := oldVersionItems(removable, "Old JetBrains IDE versions", wl)
+	items = append(items, withRisk(oldItems, config.RiskMedium)...)
+
 	return items
 }
 
@@ -177,13 +237,13 @@ func CleanGoModCache(dryRun bool) (int64, error) {
 // goModCachePath returns the Go module cache directory path.
 func goModCachePath() string {
 	// Check GOMODCACHE first, then GOPATH/pkg/mod/cache, then default.
-	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+	if modCache := config.ToolEnv("GOMODCACHE"); modCache != "" {
 		if _, err := os.Stat(modCache); err == nil {
 			return modCache
 		}
 	}
 
-	gopath := os.Getenv("GOPATH")
+	gopath := config.ToolEnv("GOPATH")
 	if gopath == "" {
 		gopath = filepath.Join(os.Getenv("USERPROFILE"), "go")
 	}
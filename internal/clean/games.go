@@ -0,0 +1,143 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Steam Caches ────────────────────────────────────────────────────────────
+// Steam installs games into any number of library folders, each with its
+// own steamapps\shadercache. Libraries are read from libraryfolders.vdf
+// instead of assuming the default install path. Static GPU-driver and
+// launcher caches are ordinary "games" config targets; the Steam folders
+// themselves are listed once, in config.SteamCacheDirs.
+
+// ScanSteamCaches scans Steam's launcher caches and the shader caches of
+// every Steam library. Steam is installed once per machine, so this is
+// scanned once, not per profile; see ScanSteamUserCaches. Returns nil if
+// Steam is not installed.
+func ScanSteamCaches(wl *whitelist.Whitelist) []CleanItem {
+	steamRoot := steamInstallPath()
+	if steamRoot == "" {
+		return nil
+	}
+
+	var items []CleanItem
+	libs := steamLibraries(steamRoot)
+	for _, def := range config.SteamCacheDirs() {
+		switch def.Base {
+		case config.SteamInstall:
+			items = append(items, scanSteamDir(filepath.Join(steamRoot, def.Rel), def.Description, wl)...)
+		case config.SteamLibrary:
+			for _, lib := range libs {
+				items = append(items, scanSteamDir(filepath.Join(lib, def.Rel), def.Description, wl)...)
+			}
+		}
+	}
+	return items
+}
+
+// ScanSteamUserCaches scans the Steam caches kept in the current user's
+// %LOCALAPPDATA%.
+func ScanSteamUserCaches(wl *whitelist.Whitelist) []CleanItem {
+	var items []CleanItem
+	for _, def := range config.SteamCacheDirs() {
+		if def.Base == config.SteamLocal {
+			dir := filepath.Join(os.Getenv("LOCALAPPDATA"), "Steam", def.Rel)
+			items = append(items, scanSteamDir(dir, def.Description, wl)...)
+		}
+	}
+	return items
+}
+
+// scanSteamDir scans one Steam cache folder, if it exists.
+func scanSteamDir(dir, description string, wl *whitelist.Whitelist) []CleanItem {
+	if wl != nil && wl.IsWhitelisted(dir) {
+		return nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+	return withRisk(scanDirectory(dir, "games", description, wl), targetRisk("SteamCache"))
+}
+
+// steamLibraries returns every Steam library folder, starting with the
+// install directory itself. Paths from libraryfolders.vdf are rebased onto
+// the alternate root, if set, so an image's libraries are never resolved
+// to the live drives. Libraries that no longer exist (e.g. unplugged
+// drives) are skipped.
+func steamLibraries(steamRoot string) []string {
+	libs := []string{steamRoot}
+	seen := map[string]bool{strings.ToLower(filepath.Clean(steamRoot)): true}
+
+	data, err := os.ReadFile(filepath.Join(steamRoot, "steamapps", "libraryfolders.vdf"))
+	if err != nil {
+		return libs
+	}
+	paths, err := steamLibraryPaths(string(data))
+	if err != nil {
+		return libs
+	}
+
+	for _, p := range paths {
+		p = config.Rebase(p)
+		key := strings.ToLower(filepath.Clean(p))
+		if seen[key] {
+			continue
+		}
+		if _, statErr := os.Stat(p); statErr != nil {
+			continue
+		}
+		seen[key] = true
+		libs = append(libs, p)
+	}
+	return libs
+}
+
+// steamInstallPath returns the Steam install directory from the registry
+// (HKCU\Software\Valve\Steam\SteamPath), falling back to the default
+// location. The registry describes the live system, so only the default
+// location is checked under an alternate root (see config.SetRoot).
+// Returns "" if Steam is not installed.
+func steamInstallPath() string {
+	if config.Root() == "" {
+		if path := steamRegistryPath(); path != "" {
+			return path
+		}
+	}
+
+	for _, env := range []string{"ProgramFiles(x86)", "ProgramFiles"} {
+		if base := os.Getenv(env); base != "" {
+			path := filepath.Join(base, "Steam")
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// steamRegistryPath returns the existing Steam directory recorded in the
+// registry, or "".
+func steamRegistryPath() string {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Valve\Steam`, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	path, _, valErr := key.GetStringValue("SteamPath")
+	key.Close()
+	if valErr != nil || path == "" {
+		return ""
+	}
+	path = filepath.Clean(filepath.FromSlash(path))
+	if _, statErr := os.Stat(path); statErr != nil {
+		return ""
+	}
+	return path
+}
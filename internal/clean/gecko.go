@@ -0,0 +1,206 @@
+package clean
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Gecko Browser Definitions ───────────────────────────────────────────────
+// Firefox and its forks keep profile data under %APPDATA% and the
+// disposable caches under %LOCALAPPDATA%. Which profiles exist — and where
+// they live — is recorded in profiles.ini and installs.ini, so profiles
+// moved with the Profile Manager are found as well.
+
+// geckoBrowser describes a Firefox-family browser's data directories.
+type geckoBrowser struct {
+	name    string // Human-readable browser name.
+	image   string // Process image name (e.g. "firefox.exe").
+	roaming string // Directory under %APPDATA% holding profiles.ini.
+	local   string // Directory under %LOCALAPPDATA% holding profile caches.
+}
+
+// geckoBrowsers lists the Gecko-family browsers WinMole knows about.
+// Firefox Developer Edition and Nightly share Firefox's profiles.ini and
+// are found through its per-install entries.
+var geckoBrowsers = []geckoBrowser{
+	{name: "Firefox", image: "firefox.exe", roaming: filepath.Join("Mozilla", "Firefox"), local: filepath.Join("Mozilla", "Firefox")},
+	{name: "LibreWolf", image: "librewolf.exe", roaming: "librewolf", local: "librewolf"},
+	{name: "Waterfox", image: "waterfox.exe", roaming: "Waterfox", local: "Waterfox"},
+	{name: "Floorp", image: "floorp.exe", roaming: "Floorp", local: "Floorp"},
+	{name: "Zen", image: "zen.exe", roaming: "zen", local: "zen"},
+}
+
+// geckoCacheSubdirs are the disposable directories within a profile's
+// local directory. Everything else in a profile is left alone.
+var geckoCacheSubdirs = []string{"cache2", "startupCache", "thumbnails"}
+
+// ─── Gecko Cache Scanning ────────────────────────────────────────────────────
+
+// scanGeckoCaches scans the cache directories of every profile of every
+// installed Gecko-family browser.
+func scanGeckoCaches(roaming, local string, wl *whitelist.Whitelist) []CleanItem {
+	var items []CleanItem
+	for _, b := range geckoBrowsers {
+		desc := b.name + " cache"
+		for _, dir := range geckoLocalProfileDirs(b, roaming, local) {
+			for _, subdir := range geckoCacheSubdirs {
+				cacheDir := filepath.Join(dir, subdir)
+				if _, err := os.Stat(cacheDir); err != nil {
+					continue
+				}
+				for _, item := range scanDirectory(cacheDir, "browser", desc, wl) {
+					item.Browser = b.name
+					items = append(items, item)
+				}
+			}
+		}
+	}
+	return items
+}
+
+// geckoLocalProfileDirs returns the local (cache) directory of each profile
+// of browser b.
+func geckoLocalProfileDirs(b geckoBrowser, roaming, local string) []string {
+	return geckoProfileDirs(filepath.Join(roaming, b.roaming), filepath.Join(local, b.local))
+}
+
+// geckoRoamingProfileDirs returns the roaming directory of each profile of
+// browser b, which holds the profile's own data (history, cookies,
+// sessions) as opposed to its caches.
+func geckoRoamingProfileDirs(b geckoBrowser, roaming string) []string {
+	rootDir := filepath.Join(roaming, b.roaming)
+	return geckoProfileDirs(rootDir, rootDir)
+}
+
+// geckoProfileDirs resolves the profiles recorded in rootDir's ini files
+// against baseDir. Existing directories under baseDir\Profiles are included
+// as well so profiles missing from the ini files are still found.
+func geckoProfileDirs(rootDir, baseDir string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		key := strings.ToLower(filepath.Clean(dir))
+		if seen[key] {
+			return
+		}
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return
+		}
+		seen[key] = true
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	for _, p := range readGeckoProfiles(rootDir) {
+		add(p.resolve(baseDir))
+	}
+
+	globbed, _ := filepath.Glob(filepath.Join(baseDir, "Profiles", "*"))
+	for _, dir := range globbed {
+		add(dir)
+	}
+
+	sort.Strings(dirs)
+	return dirs
+}
+
+// ─── profiles.ini / installs.ini ─────────────────────────────────────────────
+
+// geckoProfile is a profile location recorded in profiles.ini or installs.ini.
+type geckoProfile struct {
+	path       string // Path as written in the ini file.
+	isRelative bool   // Path is relative to the browser's root directory.
+}
+
+// resolve returns the profile's directory under base. Relative profiles
+// live under the browser's roaming root and keep their caches under the
+// matching local root (%LOCALAPPDATA%); profiles at a custom absolute
+// location keep data and caches in that one directory, which is rebased
+// onto the alternate root if one is set (see config.Rebase).
+func (p geckoProfile) resolve(base string) string {
+	if p.isRelative {
+		return filepath.Join(base, filepath.FromSlash(p.path))
+	}
+	return config.Rebase(filepath.FromSlash(p.path))
+}
+
+// readGeckoProfiles reads every profile recorded in rootDir's profiles.ini
+// ([ProfileN] and [Install...] sections) and installs.ini (per-install
+// defaults, used by Developer Edition and Nightly). Missing files yield no
+// profiles.
+func readGeckoProfiles(rootDir string) []geckoProfile {
+	var profiles []geckoProfile
+
+	if data, err := os.ReadFile(filepath.Join(rootDir, "profiles.ini")); err == nil {
+		for _, sec := range parseINI(data) {
+			switch {
+			case strings.HasPrefix(sec.name, "Profile"):
+				if path := sec.values["Path"]; path != "" {
+					profiles = append(profiles, geckoProfile{
+						path:       path,
+						isRelative: sec.values["IsRelative"] != "0",
+					})
+				}
+			case strings.HasPrefix(sec.name, "Install"):
+				if path := sec.values["Default"]; path != "" {
+					profiles = append(profiles, installProfile(path))
+				}
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(rootDir, "installs.ini")); err == nil {
+		for _, sec := range parseINI(data) {
+			if path := sec.values["Default"]; path != "" {
+				profiles = append(profiles, installProfile(path))
+			}
+		}
+	}
+
+	return profiles
+}
+
+// installProfile builds a profile from an install's Default= entry, which
+// is relative unless it is an absolute path.
+func installProfile(path string) geckoProfile {
+	return geckoProfile{path: path, isRelative: !filepath.IsAbs(filepath.FromSlash(path))}
+}
+
+// iniSection is a named section of an ini file.
+type iniSection struct {
+	name   string
+	values map[string]string
+}
+
+// parseINI parses a simple ini file into its sections, in file order.
+// Comments (; or #) and keys outside any section are ignored.
+func parseINI(data []byte) []iniSection {
+	var sections []iniSection
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, iniSection{
+				name:   strings.TrimSpace(line[1 : len(line)-1]),
+				values: make(map[string]string),
+			})
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || len(sections) == 0 {
+			continue
+		}
+		sections[len(sections)-1].values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return sections
+}
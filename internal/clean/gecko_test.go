@@ -0,0 +1,88 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+)
+
+func TestParseINI(t *testing.T) {
+	data := []byte("\xef\xbb\xbf; comment\n[General]\nStartWithLastProfile=1\n\n[Profile0]\nName=default\nIsRelative=1\nPath=Profiles/abc.default\n")
+
+	sections := parseINI(data)
+	if len(sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(sections))
+	}
+	if sections[1].name != "Profile0" || sections[1].values["Path"] != "Profiles/abc.default" {
+		t.Errorf("unexpected section: %+v", sections[1])
+	}
+}
+
+func TestGeckoLocalProfileDirs(t *testing.T) {
+	roaming := t.TempDir()
+	local := t.TempDir()
+	custom := t.TempDir()
+
+	b := geckoBrowser{name: "Firefox", roaming: "Firefox", local: "Firefox"}
+	rootDir := filepath.Join(roaming, b.roaming)
+	localRoot := filepath.Join(local, b.local)
+
+	ini := "[Profile0]\nName=default\nIsRelative=1\nPath=Profiles/abc.default-release\n\n" +
+		"[Profile1]\nName=work\nIsRelative=0\nPath=" + custom + "\n\n" +
+		"[Install308046B0AF4A39CB]\nDefault=Profiles/abc.default-release\n"
+	installs := "[6F193CCC56814779]\nDefault=Profiles/xyz.dev-edition-default\n"
+
+	for _, dir := range []string{
+		rootDir,
+		filepath.Join(localRoot, "Profiles", "abc.default-release"),
+		filepath.Join(localRoot, "Profiles", "xyz.dev-edition-default"),
+		filepath.Join(localRoot, "Profiles", "orphan.default"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "profiles.ini"), []byte(ini), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "installs.ini"), []byte(installs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := geckoLocalProfileDirs(b, roaming, local)
+	want := map[string]bool{
+		filepath.Join(localRoot, "Profiles", "abc.default-release"):     true,
+		filepath.Join(localRoot, "Profiles", "xyz.dev-edition-default"): true,
+		filepath.Join(localRoot, "Profiles", "orphan.default"):          true,
+		filepath.Clean(custom): true,
+	}
+	if len(dirs) != len(want) {
+		t.Fatalf("got %v, want %d dirs", dirs, len(want))
+	}
+	for _, d := range dirs {
+		if !want[d] {
+			t.Errorf("unexpected profile dir %s", d)
+		}
+	}
+}
+
+func TestGeckoProfileResolve_RebasesAbsoluteUnderRoot(t *testing.T) {
+	// SetRoot rebinds the system-wide variables; restore them afterwards.
+	for _, name := range []string{"SystemDrive", "SystemRoot", "windir", "ProgramData",
+		"ALLUSERSPROFILE", "ProgramFiles", "ProgramFiles(x86)", "PUBLIC"} {
+		t.Setenv(name, os.Getenv(name))
+	}
+	root := t.TempDir()
+	if err := config.SetRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.SetRoot("") })
+
+	p := geckoProfile{path: `D:\Profiles\work`}
+	root = config.Root()
+	if got, want := p.resolve(`C:\base`), filepath.Join(root, "Profiles", "work"); got != want {
+		t.Errorf("resolve = %q, want %q", got, want)
+	}
+}
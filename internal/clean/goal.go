@@ -0,0 +1,149 @@
+package clean
+
+import (
+	"sort"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+)
+
+// ─── Goal Planning ───────────────────────────────────────────────────────────
+
+// GoalCandidate is a single cleanable unit considered by the goal planner.
+// Scan results and API-backed targets (Recycle Bin, Go module cache,
+// Windows.old) are both expressed as candidates so they compete equally.
+type GoalCandidate struct {
+	// Name is the target name (e.g. "ChromeCache", "RecycleBin"), prefixed
+	// with the user when scanning all users (see ScanResult.Key).
+	Name string
+
+	// Category is the high-level grouping (user, browser, dev, system).
+	Category string
+
+	// Size is the number of bytes cleaning this candidate would free.
+	Size int64
+
+	// RiskLevel is one of "low", "medium", "high".
+	RiskLevel string
+}
+
+// GoalPlan is the outcome of PlanForGoal: the candidates chosen to reach
+// a free-space goal, in the order they were picked.
+type GoalPlan struct {
+	// Goal is the requested number of bytes to free.
+	Goal int64
+
+	// MaxRisk is the highest risk level that was allowed.
+	MaxRisk string
+
+	// Selected lists the chosen candidates, safest first.
+	Selected []GoalCandidate
+
+	// Total is the combined size of the selected candidates.
+	Total int64
+
+	// Excluded lists candidates skipped for exceeding MaxRisk.
+	Excluded []GoalCandidate
+
+	// Reachable is true when Total meets or exceeds Goal.
+	Reachable bool
+}
+
+// RiskTotal summarizes the selected candidates at one risk level.
+type RiskTotal struct {
+	RiskLevel string
+	Size      int64
+	Count     int
+}
+
+// CandidatesFromResults converts scan results into goal candidates.
+func CandidatesFromResults(results []ScanResult) []GoalCandidate {
+	candidates := make([]GoalCandidate, 0, len(results))
+	for _, r := range results {
+		if r.TotalSize <= 0 {
+			continue
+		}
+		candidates = append(candidates, GoalCandidate{
+			Name:      r.Key(),
+			Category:  r.Group(),
+			Size:      r.TotalSize,
+			RiskLevel: r.RiskLevel,
+		})
+	}
+	return candidates
+}
+
+// PlanForGoal picks candidates until at least goal bytes would be freed.
+// Candidates are taken from the lowest risk level upwards, largest first
+// within each level, so the goal is met with the fewest and safest
+// targets. Candidates above maxRisk are never chosen.
+func PlanForGoal(candidates []GoalCandidate, goal int64, maxRisk string) GoalPlan {
+	plan := GoalPlan{Goal: goal, MaxRisk: maxRisk}
+
+	ceiling := config.RiskRank(maxRisk)
+	eligible := make([]GoalCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Size <= 0 {
+			continue
+		}
+		if config.RiskRank(c.RiskLevel) > ceiling {
+			plan.Excluded = append(plan.Excluded, c)
+			continue
+		}
+		eligible = append(eligible, c)
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		ri, rj := config.RiskRank(eligible[i].RiskLevel), config.RiskRank(eligible[j].RiskLevel)
+		if ri != rj {
+			return ri < rj
+		}
+		if eligible[i].Size != eligible[j].Size {
+			return eligible[i].Size > eligible[j].Size
+		}
+		return eligible[i].Name < eligible[j].Name
+	})
+
+	for _, c := range eligible {
+		if plan.Total >= goal {
+			break
+		}
+		plan.Selected = append(plan.Selected, c)
+		plan.Total += c.Size
+	}
+
+	plan.Reachable = plan.Total >= goal
+	return plan
+}
+
+// IsSelected reports whether the named candidate is part of the plan.
+func (p GoalPlan) IsSelected(name string) bool {
+	for _, c := range p.Selected {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RiskBreakdown returns per-risk-level totals for the selected candidates,
+// ordered from low to high. Levels with no selected candidates are omitted.
+func (p GoalPlan) RiskBreakdown() []RiskTotal {
+	levels := []string{config.RiskLow, config.RiskMedium, config.RiskHigh}
+	totals := make([]RiskTotal, len(levels))
+	for i, level := range levels {
+		totals[i].RiskLevel = level
+	}
+	for _, c := range p.Selected {
+		t := &totals[config.RiskRank(c.RiskLevel)]
+		t.Size += c.Size
+		t.Count++
+	}
+
+	var out []RiskTotal
+	for _, t := range totals {
+		if t.Count > 0 {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package clean
+
+import "testing"
+
+func TestPlanForGoal_PrefersLowRisk(t *testing.T) {
+	candidates := []GoalCandidate{
+		{Name: "WindowsOld", Size: 30 << 30, RiskLevel: "high"},
+		{Name: "NuGet", Size: 5 << 30, RiskLevel: "medium"},
+		{Name: "UserTemp", Size: 2 << 30, RiskLevel: "low"},
+		{Name: "ChromeCache", Size: 4 << 30, RiskLevel: "low"},
+	}
+
+	plan := PlanForGoal(candidates, 8<<30, "high")
+
+	if !plan.Reachable {
+		t.Fatalf("goal should be reachable, total = %d", plan.Total)
+	}
+	want := []string{"ChromeCache", "UserTemp", "NuGet"}
+	if len(plan.Selected) != len(want) {
+		t.Fatalf("selected %d candidates, want %d: %+v", len(plan.Selected), len(want), plan.Selected)
+	}
+	for i, name := range want {
+		if plan.Selected[i].Name != name {
+			t.Errorf("selected[%d] = %q, want %q", i, plan.Selected[i].Name, name)
+		}
+	}
+	if plan.IsSelected("WindowsOld") {
+		t.Error("high-risk target should not be chosen when the goal is met earlier")
+	}
+}
+
+func TestPlanForGoal_RespectsMaxRisk(t *testing.T) {
+	candidates := []GoalCandidate{
+		{Name: "WindowsOld", Size: 30 << 30, RiskLevel: "high"},
+		{Name: "UserTemp", Size: 1 << 30, RiskLevel: "low"},
+	}
+
+	plan := PlanForGoal(candidates, 10<<30, "medium")
+
+	if plan.Reachable {
+		t.Error("goal should be unreachable without the high-risk target")
+	}
+	if plan.IsSelected("WindowsOld") {
+		t.Error("target above --max-risk must never be chosen")
+	}
+	if len(plan.Excluded) != 1 || plan.Excluded[0].Name != "WindowsOld" {
+		t.Errorf("expected WindowsOld to be excluded, got %+v", plan.Excluded)
+	}
+	if plan.Total != 1<<30 {
+		t.Errorf("total = %d, want %d", plan.Total, int64(1<<30))
+	}
+}
+
+func TestGoalPlan_RiskBreakdown(t *testing.T) {
+	plan := GoalPlan{Selected: []GoalCandidate{
+		{Name: "a", Size: 10, RiskLevel: "low"},
+		{Name: "b", Size: 20, RiskLevel: "low"},
+		{Name: "c", Size: 5, RiskLevel: "high"},
+	}}
+
+	breakdown := plan.RiskBreakdown()
+	if len(breakdown) != 2 {
+		t.Fatalf("expected 2 risk levels, got %+v", breakdown)
+	}
+	if breakdown[0].RiskLevel != "low" || breakdown[0].Size != 30 || breakdown[0].Count != 2 {
+		t.Errorf("unexpected low-risk total: %+v", breakdown[0])
+	}
+	if breakdown[1].RiskLevel != "high" || breakdown[1].Size != 5 || breakdown[1].Count != 1 {
+		t.Errorf("unexpected high-risk total: %+v", breakdown[1])
+	}
+}
+
+func TestCandidatesFromResults_KeyedByUser(t *testing.T) {
+	alice := ItemsToResult("Chrome cache", []CleanItem{{Path: "a", Size: 10, Category: "browser"}})
+	alice.User = "alice"
+	bob := ItemsToResult("Chrome cache", []CleanItem{{Path: "b", Size: 20, Category: "browser"}})
+	bob.User = "bob"
+
+	candidates := CandidatesFromResults([]ScanResult{alice, bob})
+	if len(candidates) != 2 || candidates[0].Name == candidates[1].Name {
+		t.Fatalf("per-user results must produce distinct candidates, got %+v", candidates)
+	}
+
+	plan := PlanForGoal(candidates, 15, "high")
+	if !plan.IsSelected(bob.Key()) || plan.IsSelected(alice.Key()) {
+		t.Errorf("expected only bob's cache to be selected, got %+v", plan.Selected)
+	}
+}
@@ -0,0 +1,277 @@
+package clean
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/internal/core"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Command Runner ──────────────────────────────────────────────────────────
+
+// CommandRunner looks up and runs external commands. It is an interface so
+// native cache pruning can be tested with fakes instead of real tools.
+type CommandRunner interface {
+	// LookPath reports where the named executable is on PATH.
+	LookPath(name string) (string, error)
+
+	// Run executes the command and returns its combined output.
+	Run(name string, args ...string) ([]byte, error)
+}
+
+// execRunner is the CommandRunner backed by os/exec.
+type execRunner struct{}
+
+func (execRunner) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+func (execRunner) Run(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+// DefaultRunner runs commands on the real system.
+var DefaultRunner CommandRunner = execRunner{}
+
+// ─── Native Pruners ──────────────────────────────────────────────────────────
+
+// NativePruner describes how a developer tool cleans its own cache.
+// Running the tool's command respects its prune semantics and avoids
+// corrupting caches the tool has open, unlike deleting files directly.
+type NativePruner struct {
+	// Name is a short label (e.g. "npm").
+	Name string
+
+	// Tool is the executable to run.
+	Tool string
+
+	// Args are the arguments passed to Tool.
+	Args []string
+
+	// Detect is the executable whose presence on PATH enables this pruner.
+	// Defaults to Tool (cargo-cache is a separate plugin binary).
+	Detect string
+
+	// Description matches the CleanItem.Description produced by
+	// ScanDevCaches for the same cache, so those items can be handed over.
+	Description string
+
+	// Paths are the cache directories the command prunes. They are
+	// measured before and after it runs to report what was freed, and
+	// size the cache when the file scanner does not cover it. Defaults to
+	// the directories ScanDevCaches uses for Description.
+	Paths []string
+}
+
+// CommandLine returns the pruner's command as a display string.
+func (p NativePruner) CommandLine() string {
+	return strings.Join(append([]string{p.Tool}, p.Args...), " ")
+}
+
+// Available reports whether the pruner's tool is installed.
+func (p NativePruner) Available(r CommandRunner) bool {
+	detect := p.Detect
+	if detect == "" {
+		detect = p.Tool
+	}
+	_, err := r.LookPath(detect)
+	return err == nil
+}
+
+// Run executes the pruner's command. In dryRun mode nothing is run.
+func (p NativePruner) Run(r CommandRunner, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	output, err := r.Run(p.Tool, p.Args...)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w\n%s", p.CommandLine(), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Prune runs the pruner's command and returns the bytes it freed, measured
+// from Paths before and after. Tools like pnpm store prune or cargo cache
+// --autoclean remove only part of their cache, so the estimate made when
+// scanning would over-report.
+func (p NativePruner) Prune(r CommandRunner) (int64, error) {
+	before := p.measure()
+	if err := p.Run(r, false); err != nil {
+		return 0, err
+	}
+	return max(before-p.measure(), 0), nil
+}
+
+// protectedBy reports whether any of the pruner's Paths is whitelisted or
+// holds a whitelisted entry. The command would wipe those as well.
+func (p NativePruner) protectedBy(wl *whitelist.Whitelist) bool {
+	if wl == nil {
+		return false
+	}
+	for _, dir := range p.Paths {
+		if dir == "" {
+			continue
+		}
+		if wl.IsWhitelisted(dir) {
+			return true
+		}
+		if _, _, protected := measureDir(dir, wl); protected {
+			return true
+		}
+	}
+	return false
+}
+
+// measure returns the combined size of the pruner's cache directories.
+func (p NativePruner) measure() int64 {
+	var total int64
+	for _, dir := range p.Paths {
+		if dir == "" {
+			continue
+		}
+		if size, err := core.GetDirSize(dir); err == nil {
+			total += size
+		}
+	}
+	return total
+}
+
+// NativePruners returns the native cache commands WinMole knows about.
+func NativePruners() []NativePruner {
+	pruners := []NativePruner{
+		{
+			Name:        "npm",
+			Tool:        "npm",
+			Args:        []string{"cache", "clean", "--force"},
+			Description: "npm package cache",
+		},
+		{
+			Name:        "pip",
+			Tool:        "pip",
+			Args:        []string{"cache", "purge"},
+			Description: "Python pip cache",
+		},
+		{
+			Name:        "NuGet",
+			Tool:        "dotnet",
+			Args:        []string{"nuget", "locals", "global-packages", "--clear"},
+			Description: "NuGet package cache",
+		},
+		{
+			Name:        "Cargo",
+			Tool:        "cargo",
+			Args:        []string{"cache", "--autoclean"},
+			Detect:      "cargo-cache",
+			Description: "Rust Cargo registry cache",
+		},
+		{
+			Name:        "Go build",
+			Tool:        "go",
+			Args:        []string{"clean", "-cache"},
+			Description: "Go build cache",
+			Paths:       []string{goBuildCachePath()},
+		},
+		{
+			Name:        "pnpm",
+			Tool:        "pnpm",
+			Args:        []string{"store", "prune"},
+			Description: "pnpm content-addressable store",
+		},
+		{
+			// Classic and Berry caches both come from the Yarn cache def.
+			Name:        "Yarn",
+			Tool:        "yarn",
+			Args:        []string{"cache", "clean"},
+			Description: "Yarn package cache",
+		},
+	}
+
+	for i := range pruners {
+		if pruners[i].Paths == nil {
+			pruners[i].Paths = devCachePaths(pruners[i].Description)
+		}
+	}
+	return pruners
+}
+
+// goBuildCachePath returns the Go build cache directory (GOCACHE or the
+// Windows default under %LOCALAPPDATA%).
+func goBuildCachePath() string {
+	if dir := config.ToolEnv("GOCACHE"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.Getenv("LOCALAPPDATA"), "go-build")
+}
+
+// NativeCleanup is a planned native prune with its estimated size.
+type NativeCleanup struct {
+	Pruner NativePruner
+
+	// Size is the estimated number of bytes the command will free.
+	Size int64
+
+	// ItemCount is the number of scanned files handed over to the command.
+	ItemCount int
+
+	// RiskLevel is the highest risk level among the handed-over items.
+	RiskLevel string
+}
+
+// PlanName is the unique name used for this cleanup in goal plans.
+func (nc NativeCleanup) PlanName() string {
+	return nc.Pruner.Name + " (native)"
+}
+
+// SplitNative hands developer cache items over to native pruners whose
+// tools are installed. It returns the items that still need file deletion
+// (tools missing from PATH fall back to file deletion) and the native
+// cleanups to run instead. Pruners whose caches are whitelisted, wholly or
+// in part, are never run. Caches not covered by the file scanner are
+// sized from the pruner's Paths.
+func SplitNative(items []CleanItem, wl *whitelist.Whitelist, r CommandRunner) ([]CleanItem, []NativeCleanup) {
+	var native []NativeCleanup
+	handled := make(map[string]bool)
+
+	for _, p := range NativePruners() {
+		if !p.Available(r) || p.protectedBy(wl) {
+			continue
+		}
+
+		nc := NativeCleanup{Pruner: p, RiskLevel: config.RiskLow}
+		for _, item := range items {
+			if item.Description == p.Description {
+				nc.Size += item.Size
+				nc.ItemCount += item.Files()
+				if config.RiskRank(item.RiskLevel) > config.RiskRank(nc.RiskLevel) {
+					nc.RiskLevel = item.RiskLevel
+				}
+			}
+		}
+		if nc.ItemCount == 0 {
+			if devCachePaths(p.Description) != nil {
+				continue // Scanned, and nothing there to clean.
+			}
+			nc.Size = p.measure()
+		}
+		if nc.Size == 0 {
+			continue
+		}
+
+		handled[p.Description] = true
+		native = append(native, nc)
+	}
+
+	remaining := make([]CleanItem, 0, len(items))
+	for _, item := range items {
+		if !handled[item.Description] {
+			remaining = append(remaining, item)
+		}
+	}
+
+	return remaining, native
+}
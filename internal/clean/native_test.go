@@ -0,0 +1,166 @@
+package clean
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// fakeRunner is a CommandRunner that only "has" the listed tools and
+// records every command it is asked to run.
+type fakeRunner struct {
+	installed map[string]bool
+	calls     []string
+	fail      bool
+}
+
+func (f *fakeRunner) LookPath(name string) (string, error) {
+	if f.installed[name] {
+		return `C:\tools\` + name + ".exe", nil
+	}
+	return "", errors.New("not found")
+}
+
+func (f *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.Join(append([]string{name}, args...), " "))
+	if f.fail {
+		return []byte("boom"), errors.New("exit status 1")
+	}
+	return nil, nil
+}
+
+func TestSplitNative_HandsOverInstalledToolsOnly(t *testing.T) {
+	items := []CleanItem{
+		{Path: `C:\a\npm-cache\x`, Size: 100, Description: "npm package cache"},
+		{Path: `C:\a\npm-cache\y`, Size: 50, Description: "npm package cache"},
+		{Path: `C:\a\pip\z`, Size: 70, Description: "Python pip cache"},
+		{Path: `C:\a\gradle\w`, Size: 30, Description: "Gradle build cache"},
+	}
+	runner := &fakeRunner{installed: map[string]bool{"npm": true}}
+
+	remaining, native := SplitNative(items, nil, runner)
+
+	if len(native) != 1 || native[0].Pruner.Name != "npm" {
+		t.Fatalf("expected only npm to be handled natively, got %+v", native)
+	}
+	if native[0].Size != 150 || native[0].ItemCount != 2 {
+		t.Errorf("npm cleanup = %d bytes / %d items, want 150 / 2", native[0].Size, native[0].ItemCount)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("pip (missing tool) and Gradle should fall back to file deletion, got %+v", remaining)
+	}
+}
+
+func TestSplitNative_SkipsWhitelistedCaches(t *testing.T) {
+	roaming := t.TempDir()
+	t.Setenv("APPDATA", roaming)
+	cache := filepath.Join(roaming, "npm-cache")
+	if err := os.MkdirAll(filepath.Join(cache, "_cacache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cache, "_cacache", "index"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner := &fakeRunner{installed: map[string]bool{"npm": true}}
+
+	// No items because the whole cache is whitelisted: npm must not run.
+	wl, err := whitelist.Load(filepath.Join(t.TempDir(), "whitelist.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wl.Add(cache); err != nil {
+		t.Fatal(err)
+	}
+	if _, native := SplitNative(nil, wl, runner); len(native) != 0 {
+		t.Errorf("whitelisted npm cache was handed to npm: %+v", native)
+	}
+
+	// Part of the cache is whitelisted: the rest stays file items.
+	wl, err = whitelist.Load(filepath.Join(t.TempDir(), "whitelist.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wl.Add(filepath.Join(cache, "_cacache", "index")); err != nil {
+		t.Fatal(err)
+	}
+	items := []CleanItem{{Path: filepath.Join(cache, "other"), Size: 10, Description: "npm package cache"}}
+	remaining, native := SplitNative(items, wl, runner)
+	if len(native) != 0 || len(remaining) != 1 {
+		t.Errorf("partly whitelisted npm cache: native %+v, remaining %+v", native, remaining)
+	}
+
+	// A scanned cache with nothing in it is not pruned either.
+	if _, native := SplitNative(nil, nil, runner); len(native) != 0 {
+		t.Errorf("npm ran although the scanner found nothing to clean: %+v", native)
+	}
+}
+
+func TestNativePruner_Run(t *testing.T) {
+	p := NativePruner{Name: "pip", Tool: "pip", Args: []string{"cache", "purge"}}
+
+	runner := &fakeRunner{}
+	if err := p.Run(runner, true); err != nil || len(runner.calls) != 0 {
+		t.Fatalf("dry run must not execute anything, calls=%v err=%v", runner.calls, err)
+	}
+
+	if err := p.Run(runner, false); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(runner.calls) != 1 || runner.calls[0] != "pip cache purge" {
+		t.Errorf("unexpected calls: %v", runner.calls)
+	}
+
+	runner.fail = true
+	if err := p.Run(runner, false); err == nil || !strings.Contains(err.Error(), "pip cache purge") {
+		t.Errorf("expected failure mentioning the command, got %v", err)
+	}
+}
+
+func TestNativePruner_PruneReportsMeasuredBytes(t *testing.T) {
+	dir := t.TempDir()
+	keep, drop := filepath.Join(dir, "keep"), filepath.Join(dir, "drop")
+	for path, size := range map[string]int{keep: 30, drop: 70} {
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The "tool" removes only part of its cache, as store prune does.
+	runner := &pruneRunner{remove: drop}
+	p := NativePruner{Name: "pnpm", Tool: "pnpm", Args: []string{"store", "prune"}, Paths: []string{dir}}
+
+	freed, err := p.Prune(runner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed != 70 {
+		t.Errorf("freed = %d, want 70 (not the 100-byte cache size)", freed)
+	}
+}
+
+func TestNativePruners_YarnCoversBerryCache(t *testing.T) {
+	for _, p := range NativePruners() {
+		if p.Name != "Yarn" {
+			continue
+		}
+		for _, path := range p.Paths {
+			if strings.Contains(path, "Berry") {
+				return
+			}
+		}
+		t.Errorf("Yarn pruner paths %v miss the Berry cache", p.Paths)
+	}
+}
+
+// pruneRunner is a CommandRunner whose commands delete one file.
+type pruneRunner struct{ remove string }
+
+func (pruneRunner) LookPath(name string) (string, error) { return name, nil }
+
+func (r *pruneRunner) Run(string, ...string) ([]byte, error) {
+	return nil, os.Remove(r.remove)
+}
@@ -0,0 +1,222 @@
+package clean
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Browser Privacy Targets ─────────────────────────────────────────────────
+// Privacy cleaning removes browsing traces (history, cookies, form data,
+// sessions) for shared kiosks and test machines. Unlike caches this data
+// is irreplaceable, so every kind is opt-in, high risk and carries its
+// own warning.
+
+// Privacy kinds accepted by ParsePrivacyKinds.
+const (
+	PrivacyHistory   = "history"
+	PrivacyCookies   = "cookies"
+	PrivacyDownloads = "downloads"
+	PrivacyForms     = "forms"
+	PrivacySessions  = "sessions"
+)
+
+// privacyTarget lists the profile files holding one kind of privacy data.
+// Paths are relative to the profile directory; directories are removed
+// with all their contents.
+type privacyTarget struct {
+	kind     string
+	label    string // Used in item descriptions (e.g. "Chrome history").
+	warning  string
+	note     string // Shown whenever the kind is selected.
+	chromium []string
+	gecko    []string
+}
+
+// privacyTargets defines every privacy kind, in display order.
+var privacyTargets = []privacyTarget{
+	{
+		kind:    PrivacyHistory,
+		label:   "history",
+		warning: "Browsing history is deleted. Firefox's places.sqlite also holds bookmarks; Firefox restores them from its automatic bookmark backup.",
+		chromium: []string{
+			"History", "History-journal",
+			"Visited Links",
+			"Top Sites", "Top Sites-journal",
+		},
+		gecko: []string{"places.sqlite", "places.sqlite-wal", "places.sqlite-shm"},
+	},
+	{
+		kind:    PrivacyCookies,
+		label:   "cookies",
+		warning: "Cookies are deleted. You will be signed out of every website.",
+		chromium: []string{
+			"Cookies", "Cookies-journal",
+			filepath.Join("Network", "Cookies"), filepath.Join("Network", "Cookies-journal"),
+		},
+		gecko: []string{"cookies.sqlite", "cookies.sqlite-wal", "cookies.sqlite-shm"},
+	},
+	{
+		// Chromium and current Firefox keep download history in the
+		// browsing history databases, which only the history kind removes.
+		kind:    PrivacyDownloads,
+		label:   "download history",
+		warning: "Download history is deleted. Downloaded files are kept.",
+		note:    "Chrome, Edge and other Chromium browsers keep download history in their History database; clear it with --privacy history.",
+		gecko:   []string{"downloads.json"},
+	},
+	{
+		kind:     PrivacyForms,
+		label:    "form data",
+		warning:  "Autofill form data is deleted. Chromium's Web Data also holds saved addresses, payment cards and custom search engines.",
+		chromium: []string{"Web Data", "Web Data-journal"},
+		gecko:    []string{"formhistory.sqlite", "formhistory.sqlite-wal", "formhistory.sqlite-shm"},
+	},
+	{
+		kind:    PrivacySessions,
+		label:   "sessions",
+		warning: "Session restore data is deleted. Open tabs and windows cannot be restored.",
+		chromium: []string{
+			"Sessions",
+			"Current Session", "Current Tabs", "Last Session", "Last Tabs",
+		},
+		gecko: []string{"sessionstore.jsonlz4", "sessionstore-backups"},
+	},
+}
+
+// PrivacyKinds returns every privacy kind, in display order.
+func PrivacyKinds() []string {
+	kinds := make([]string, len(privacyTargets))
+	for i, t := range privacyTargets {
+		kinds[i] = t.kind
+	}
+	return kinds
+}
+
+// ParsePrivacyKinds parses a comma-separated list of privacy kinds. "all"
+// selects every kind. An empty string selects none.
+func ParsePrivacyKinds(s string) ([]string, error) {
+	var kinds []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		kind := strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case kind == "":
+			continue
+		case kind == "all":
+			return PrivacyKinds(), nil
+		case privacyTargetFor(kind) == nil:
+			return nil, fmt.Errorf("unknown privacy kind %q (use %s or all)",
+				kind, strings.Join(PrivacyKinds(), ", "))
+		case !seen[kind]:
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+// PrivacyWarning returns the user-facing warning for a privacy kind.
+func PrivacyWarning(kind string) string {
+	if t := privacyTargetFor(kind); t != nil {
+		return t.warning
+	}
+	return ""
+}
+
+// PrivacyNote returns a note on what a privacy kind does not cover, or "".
+func PrivacyNote(kind string) string {
+	if t := privacyTargetFor(kind); t != nil {
+		return t.note
+	}
+	return ""
+}
+
+// privacyTargetFor returns the target definition for kind, or nil.
+func privacyTargetFor(kind string) *privacyTarget {
+	for i := range privacyTargets {
+		if privacyTargets[i].kind == kind {
+			return &privacyTargets[i]
+		}
+	}
+	return nil
+}
+
+// ─── Privacy Scanning ────────────────────────────────────────────────────────
+
+// ScanBrowserPrivacy scans the selected privacy kinds in every profile of
+// every installed browser. All items are high risk and use the "privacy"
+// category. Items of running browsers are marked InUse exactly like
+// ScanBrowserCaches; procs may be nil to skip process detection.
+func ScanBrowserPrivacy(kinds []string, wl *whitelist.Whitelist, procs ProcessLister) []CleanItem {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	local := os.Getenv("LOCALAPPDATA")
+	roaming := os.Getenv("APPDATA")
+
+	var items []CleanItem
+	for _, kind := range kinds {
+		t := privacyTargetFor(kind)
+		if t == nil {
+			continue
+		}
+
+		for _, b := range chromiumBrowsers(local, roaming) {
+			profiles := b.profiles()
+			for _, profile := range profiles {
+				desc := b.name + " " + t.label
+				if len(profiles) > 1 && profile.name != "" {
+					desc += " (" + profile.name + ")"
+				}
+				items = append(items, privacyItems(profile.dir, t.chromium, b.name, desc, wl)...)
+			}
+		}
+
+		for _, b := range geckoBrowsers {
+			for _, dir := range geckoRoamingProfileDirs(b, roaming) {
+				desc := b.name + " " + t.label
+				items = append(items, privacyItems(dir, t.gecko, b.name, desc, wl)...)
+			}
+		}
+	}
+
+	if procs != nil {
+		markRunning(items, procs)
+	}
+	return items
+}
+
+// privacyItems collects the existing files (and directory contents) named
+// by rels within profileDir.
+func privacyItems(profileDir string, rels []string, browser, desc string, wl *whitelist.Whitelist) []CleanItem {
+	var items []CleanItem
+	for _, rel := range rels {
+		path := filepath.Join(profileDir, rel)
+		if wl != nil && wl.IsWhitelisted(path) {
+			continue
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			continue
+		}
+
+		var found []CleanItem
+		if info.IsDir() {
+			found = scanDirectory(path, "privacy", desc, wl)
+		} else {
+			found = []CleanItem{{Path: path, Size: info.Size(), Category: "privacy", Description: desc}}
+		}
+		for _, item := range found {
+			item.Browser = browser
+			item.RiskLevel = config.RiskHigh
+			items = append(items, item)
+		}
+	}
+	return items
+}
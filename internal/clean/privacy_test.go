@@ -0,0 +1,76 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+)
+
+func TestParsePrivacyKinds(t *testing.T) {
+	kinds, err := ParsePrivacyKinds(" Cookies,history,cookies ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kinds) != 2 || kinds[0] != PrivacyCookies || kinds[1] != PrivacyHistory {
+		t.Errorf("got %v, want [cookies history]", kinds)
+	}
+
+	if kinds, _ := ParsePrivacyKinds(""); len(kinds) != 0 {
+		t.Errorf("empty string should select nothing, got %v", kinds)
+	}
+	if kinds, _ := ParsePrivacyKinds("all"); len(kinds) != len(privacyTargets) {
+		t.Errorf("all should select every kind, got %v", kinds)
+	}
+	if _, err := ParsePrivacyKinds("passwords"); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+func TestPrivacyItems(t *testing.T) {
+	profile := t.TempDir()
+	for _, rel := range []string{
+		"History",
+		filepath.Join("Network", "Cookies"),
+		filepath.Join("Sessions", "Session_1"),
+		"Bookmarks",
+	} {
+		path := filepath.Join(profile, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var rels []string
+	for _, kind := range PrivacyKinds() {
+		rels = append(rels, privacyTargetFor(kind).chromium...)
+	}
+	items := privacyItems(profile, rels, "Chrome", "Chrome privacy", nil)
+
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3: %+v", len(items), items)
+	}
+	for _, item := range items {
+		if filepath.Base(item.Path) == "Bookmarks" {
+			t.Errorf("bookmarks must never be a privacy target")
+		}
+		if item.RiskLevel != config.RiskHigh || item.Category != "privacy" || item.Browser != "Chrome" {
+			t.Errorf("unexpected item metadata: %+v", item)
+		}
+	}
+}
+
+func TestPrivacyDownloads_LeavesChromiumToHistory(t *testing.T) {
+	// Chromium download history lives in the History database; deleting
+	// other files would report success while clearing nothing.
+	if files := privacyTargetFor(PrivacyDownloads).chromium; len(files) != 0 {
+		t.Errorf("downloads must not delete Chromium files, got %v", files)
+	}
+	if PrivacyNote(PrivacyDownloads) == "" {
+		t.Error("downloads should point Chromium users to the history kind")
+	}
+}
@@ -0,0 +1,106 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/core"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Empty Directory Pruning ─────────────────────────────────────────────────
+// Scanners collect files only, so a cleanup leaves %TEMP% and cache trees
+// full of empty folder skeletons. The optional prune pass removes them
+// bottom-up beneath each scanned root. Roots themselves are always kept,
+// and whitelisted folders are neither entered nor removed.
+
+// PruneResult reports the outcome of PruneEmptyDirs.
+type PruneResult struct {
+	// Removed lists the directories that were deleted.
+	Removed []string
+
+	// Failed counts empty directories that could not be deleted (locked,
+	// access denied or protected).
+	Failed int
+}
+
+// PruneRoots returns the distinct scanned roots of all items in results,
+// sorted.
+func PruneRoots(results []ScanResult) []string {
+	seen := make(map[string]bool)
+	var roots []string
+	add := func(root string) {
+		if root == "" {
+			return
+		}
+		key := strings.ToLower(filepath.Clean(root))
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		roots = append(roots, filepath.Clean(root))
+	}
+	for _, r := range results {
+		for _, item := range r.Items {
+			add(item.Root)
+		}
+		if r.Source != nil {
+			for _, root := range r.Source.Roots {
+				add(root)
+			}
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// PruneEmptyDirs removes every directory beneath roots that is empty or
+// contains only empty directories. No root is ever removed, even when it
+// lies inside another root. Symlinks and junctions are not followed.
+func PruneEmptyDirs(roots []string, wl *whitelist.Whitelist) PruneResult {
+	keep := make(map[string]bool, len(roots))
+	for _, root := range roots {
+		keep[strings.ToLower(filepath.Clean(root))] = true
+	}
+
+	var res PruneResult
+	for _, root := range roots {
+		if wl != nil && wl.IsWhitelisted(root) {
+			continue
+		}
+		pruneDir(filepath.Clean(root), keep, wl, &res)
+	}
+	return res
+}
+
+// pruneDir prunes the subdirectories of dir and reports whether dir is
+// empty afterwards.
+func pruneDir(dir string, keep map[string]bool, wl *whitelist.Whitelist, res *PruneResult) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	remaining := len(entries)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue // Files, symlinks and junctions stay.
+		}
+		path := filepath.Join(dir, e.Name())
+		if wl != nil && wl.IsWhitelisted(path) {
+			continue
+		}
+		if !pruneDir(path, keep, wl, res) || keep[strings.ToLower(path)] {
+			continue
+		}
+		if core.ValidatePath(path) != nil || os.Remove(path) != nil {
+			res.Failed++
+			continue
+		}
+		res.Removed = append(res.Removed, path)
+		remaining--
+	}
+	return remaining == 0
+}
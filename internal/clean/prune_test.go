@@ -0,0 +1,119 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+func mkdirs(t *testing.T, base string, dirs ...string) {
+	t.Helper()
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(base, filepath.FromSlash(d)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestPruneEmptyDirs_RemovesSkeletonKeepsRoot(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "a/b/c", "a/d", "e", "keep")
+	if err := os.WriteFile(filepath.Join(root, "keep", "file.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := PruneEmptyDirs([]string{root}, nil)
+
+	if len(res.Removed) != 5 { // a/b/c, a/b, a/d, a, e
+		t.Errorf("removed %d dirs, want 5: %v", len(res.Removed), res.Removed)
+	}
+	if !exists(root) {
+		t.Error("root must never be removed")
+	}
+	if !exists(filepath.Join(root, "keep", "file.txt")) {
+		t.Error("non-empty directory must be kept")
+	}
+	for _, gone := range []string{"a", "e"} {
+		if exists(filepath.Join(root, gone)) {
+			t.Errorf("%s should have been pruned", gone)
+		}
+	}
+}
+
+func TestPruneEmptyDirs_RespectsWhitelist(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "cache/protected/inner", "cache/other")
+
+	wl, err := whitelist.Load(filepath.Join(t.TempDir(), "whitelist.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wl.Add(filepath.Join(root, "cache", "protected")); err != nil {
+		t.Fatal(err)
+	}
+
+	PruneEmptyDirs([]string{root}, wl)
+
+	if !exists(filepath.Join(root, "cache", "protected", "inner")) {
+		t.Error("whitelisted directory and its contents must be kept")
+	}
+	if exists(filepath.Join(root, "cache", "other")) {
+		t.Error("empty non-whitelisted sibling should be pruned")
+	}
+}
+
+func TestPruneEmptyDirs_KeepsNestedRoots(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "Temp")
+	mkdirs(t, root, "Temp/sub")
+
+	res := PruneEmptyDirs([]string{root, nested}, nil)
+
+	if !exists(nested) {
+		t.Error("a root nested inside another root must be kept")
+	}
+	if exists(filepath.Join(nested, "sub")) {
+		t.Error("empty dir under the nested root should be pruned")
+	}
+	if len(res.Removed) != 1 {
+		t.Errorf("removed %v, want only Temp/sub", res.Removed)
+	}
+}
+
+func TestPruneRoots_DistinctSorted(t *testing.T) {
+	results := []ScanResult{
+		ItemsToResult("b", []CleanItem{
+			{Path: filepath.Join("y", "f1"), Root: "y"},
+			{Path: filepath.Join("y", "f2"), Root: "y"},
+		}),
+		ItemsToResult("a", []CleanItem{
+			{Path: filepath.Join("x", "f"), Root: "x"},
+			{Path: "single.dmp"},
+		}),
+	}
+
+	got := PruneRoots(results)
+	if len(got) != 2 || got[0] != "x" || got[1] != "y" {
+		t.Errorf("PruneRoots = %v, want [x y]", got)
+	}
+}
+
+func TestScanDirectory_SetsRoot(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "sub")
+	if err := os.WriteFile(filepath.Join(root, "sub", "f.tmp"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	items := scanDirectory(root, "user", "test", nil)
+	if len(items) != 1 || items[0].Root != root {
+		t.Errorf("scanDirectory items = %+v, want one item with Root %q", items, root)
+	}
+}
@@ -0,0 +1,328 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Version-Aware Retention ─────────────────────────────────────────────────
+// Some caches keep one directory per tool version (Gradle distributions,
+// VS Code extensions, JetBrains IDE dirs, rustup toolchains). Wiping them
+// all forces large re-downloads, so only versions older than the newest
+// N per product are offered for cleanup.
+
+// versionedDir is a directory in a versioned cache layout.
+type versionedDir struct {
+	path    string
+	key     string // product identifier shared by all versions
+	version string
+}
+
+// selectOldVersions groups dirs by key and returns every directory except
+// the newest keep versions in each group. keep < 1 is treated as 1 so the
+// current version is never removed.
+func selectOldVersions(dirs []versionedDir, keep int) []versionedDir {
+	if keep < 1 {
+		keep = 1
+	}
+
+	groups := make(map[string][]versionedDir)
+	var keys []string
+	for _, d := range dirs {
+		if _, ok := groups[d.key]; !ok {
+			keys = append(keys, d.key)
+		}
+		groups[d.key] = append(groups[d.key], d)
+	}
+	sort.Strings(keys)
+
+	var old []versionedDir
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return compareVersions(group[i].version, group[j].version) > 0
+		})
+		if len(group) > keep {
+			old = append(old, group[keep:]...)
+		}
+	}
+	return old
+}
+
+// compareVersions compares dotted/dashed version strings segment by
+// segment, numerically where both segments are numbers. Returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	split := func(s string) []string {
+		return strings.FieldsFunc(s, func(r rune) bool {
+			return r == '.' || r == '-' || r == '_' || r == '+'
+		})
+	}
+	as, bs := split(a), split(b)
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		// A trailing numeric segment is newer ("7.6.1" > "7.6"); a trailing
+		// tag marks a pre-release ("8.0-rc-1" < "8.0").
+		if i >= len(as) {
+			if isNumeric(bs[i]) {
+				return -1
+			}
+			return 1
+		}
+		if i >= len(bs) {
+			if isNumeric(as[i]) {
+				return 1
+			}
+			return -1
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return 1 // Numeric segments sort above pre-release tags.
+		case bErr == nil:
+			return -1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// isNumeric reports whether s is a base-10 integer.
+func isNumeric(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// oldVersionItems turns old version directories into cleanable items. A
+// directory with no whitelisted entries becomes a single aggregate item so
+// it is removed as a whole; otherwise its files are listed individually.
+func oldVersionItems(dirs []versionedDir, description string, wl *whitelist.Whitelist) []CleanItem {
+	var items []CleanItem
+	for _, d := range dirs {
+		if wl != nil && wl.IsWhitelisted(d.path) {
+			continue
+		}
+		size, files, protected := measureDir(d.path, wl)
+		if protected {
+			items = append(items, scanDirectory(d.path, "dev", description, wl)...)
+			continue
+		}
+		if files == 0 && size == 0 {
+			continue
+		}
+		items = append(items, CleanItem{
+			Path:        d.path,
+			Size:        size,
+			Category:    "dev",
+			Description: description,
+			FileCount:   max(files, 1),
+		})
+	}
+	return items
+}
+
+// listSubdirs returns the names of the immediate subdirectories of dir.
+func listSubdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// ─── Gradle Wrapper Distributions ────────────────────────────────────────────
+
+// gradleDistPattern matches wrapper distribution dirs like "gradle-8.5-bin".
+var gradleDistPattern = regexp.MustCompile(`^gradle-(.+)-(bin|all)$`)
+
+// scanGradleDists offers Gradle wrapper distributions older than the
+// newest keep versions.
+func scanGradleDists(home string, keep int, wl *whitelist.Whitelist) []CleanItem {
+	distsDir := filepath.Join(home, ".gradle", "wrapper", "dists")
+
+	var dirs []versionedDir
+	for _, name := range listSubdirs(distsDir) {
+		m := gradleDistPattern.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		dirs = append(dirs, versionedDir{
+			path:    filepath.Join(distsDir, name),
+			key:     "gradle",
+			version: m[1],
+		})
+	}
+
+	return oldVersionItems(selectOldVersions(dirs, keep), "Old Gradle distributions", wl)
+}
+
+// ─── VS Code Extensions ──────────────────────────────────────────────────────
+
+// vscodeExtPattern splits "publisher.name-1.2.3[-platform]" into the
+// extension ID and its version.
+var vscodeExtPattern = regexp.MustCompile(`^(.+?)-(\d+\.\d+\.\d+.*)$`)
+
+// scanVSCodeExtensions offers superseded VS Code extension versions. VS Code
+// leaves old versions behind after updates; the newest keep versions of each
+// extension are always retained.
+func scanVSCodeExtensions(home string, keep int, wl *whitelist.Whitelist) []CleanItem {
+	extDir := filepath.Join(home, ".vscode", "extensions")
+
+	var dirs []versionedDir
+	for _, name := range listSubdirs(extDir) {
+		m := vscodeExtPattern.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		dirs = append(dirs, versionedDir{
+			path:    filepath.Join(extDir, name),
+			key:     strings.ToLower(m[1]),
+			version: m[2],
+		})
+	}
+
+	return oldVersionItems(selectOldVersions(dirs, keep), "Old VS Code extension versions", wl)
+}
+
+// ─── JetBrains IDE Versions ──────────────────────────────────────────────────
+
+// jetbrainsDirPattern splits "IntelliJIdea2023.2" into product and version.
+var jetbrainsDirPattern = regexp.MustCompile(`^([A-Za-z]+?)(\d{4}\.\d+)$`)
+
+// jetbrainsVersions classifies the IDE directories under jetbrainsDir into
+// the newest keep versions per product (and unversioned dirs) versus older
+// versions.
+func jetbrainsVersions(jetbrainsDir string, keep int) (current []string, old []versionedDir) {
+	var dirs []versionedDir
+	for _, name := range listSubdirs(jetbrainsDir) {
+		m := jetbrainsDirPattern.FindStringSubmatch(name)
+		if m == nil {
+			current = append(current, name)
+			continue
+		}
+		dirs = append(dirs, versionedDir{
+			path:    filepath.Join(jetbrainsDir, name),
+			key:     m[1],
+			version: m[2],
+		})
+	}
+
+	old = selectOldVersions(dirs, keep)
+	isOld := make(map[string]bool, len(old))
+	for _, d := range old {
+		isOld[d.path] = true
+	}
+	for _, d := range dirs {
+		if !isOld[d.path] {
+			current = append(current, filepath.Base(d.path))
+		}
+	}
+	sort.Strings(current)
+	return current, old
+}
+
+// ─── Rustup Toolchains ───────────────────────────────────────────────────────
+
+// rustupPinnedPattern matches pinned toolchains like "1.75.0-x86_64-pc-windows-msvc".
+var rustupPinnedPattern = regexp.MustCompile(`^(\d+\.\d+(?:\.\d+)?)-(.+)$`)
+
+// rustupNightlyPattern matches dated toolchains like "nightly-2024-01-15-x86_64-pc-windows-msvc".
+var rustupNightlyPattern = regexp.MustCompile(`^(nightly|beta)-(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+// rustToolchainsDescription is the description of old rustup toolchain items.
+const rustToolchainsDescription = "Old Rust toolchains"
+
+// scanRustupToolchains offers pinned and dated rustup toolchains older than
+// the newest keep per channel and target. Channel toolchains (stable,
+// beta, nightly) and any toolchain named in settings.toml (the default or
+// a directory override) are never offered.
+func scanRustupToolchains(home string, keep int, wl *whitelist.Whitelist) []CleanItem {
+	rustupHome := config.ToolEnv("RUSTUP_HOME")
+	if rustupHome == "" {
+		rustupHome = filepath.Join(home, ".rustup")
+	}
+	toolchainsDir := filepath.Join(rustupHome, "toolchains")
+
+	settings, _ := os.ReadFile(filepath.Join(rustupHome, "settings.toml"))
+
+	var dirs []versionedDir
+	for _, name := range listSubdirs(toolchainsDir) {
+		if strings.Contains(string(settings), `"`+name+`"`) {
+			continue // Default or override toolchain.
+		}
+		var d versionedDir
+		if m := rustupPinnedPattern.FindStringSubmatch(name); m != nil {
+			d = versionedDir{key: "pinned-" + m[2], version: m[1]}
+		} else if m := rustupNightlyPattern.FindStringSubmatch(name); m != nil {
+			d = versionedDir{key: m[1] + "-" + m[3], version: m[2]}
+		} else {
+			continue // Channel toolchain such as stable-x86_64-pc-windows-msvc.
+		}
+		d.path = filepath.Join(toolchainsDir, name)
+		dirs = append(dirs, d)
+	}
+
+	return oldVersionItems(selectOldVersions(dirs, keep), rustToolchainsDescription, wl)
+}
+
+// SplitRustup hands whole old toolchains over to `rustup toolchain
+// uninstall`, which also drops rustup's bookkeeping for them; deleting the
+// directories would leave it behind. Toolchains holding whitelisted files
+// stay file items, as does everything when rustup is not on PATH. It
+// returns the remaining items and the uninstall to run, if any.
+func SplitRustup(items []CleanItem, r CommandRunner) ([]CleanItem, []NativeCleanup) {
+	p := NativePruner{
+		Name:        "rustup",
+		Tool:        "rustup",
+		Args:        []string{"toolchain", "uninstall"},
+		Description: rustToolchainsDescription,
+	}
+	if !p.Available(r) {
+		return items, nil
+	}
+
+	nc := NativeCleanup{RiskLevel: config.RiskLow}
+	remaining := make([]CleanItem, 0, len(items))
+	for _, item := range items {
+		if item.Description != rustToolchainsDescription || item.FileCount == 0 {
+			remaining = append(remaining, item)
+			continue
+		}
+		p.Args = append(p.Args, filepath.Base(item.Path))
+		p.Paths = append(p.Paths, item.Path)
+		nc.Size += item.Size
+		nc.ItemCount += item.Files()
+		if config.RiskRank(item.RiskLevel) > config.RiskRank(nc.RiskLevel) {
+			nc.RiskLevel = item.RiskLevel
+		}
+	}
+	if len(p.Paths) == 0 {
+		return items, nil
+	}
+
+	nc.Pruner = p
+	return remaining, []NativeCleanup{nc}
+}
@@ -0,0 +1,114 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"8.5", "8.5", 0},
+		{"8.10", "8.9", 1},
+		{"7.6.1", "7.6", 1},
+		{"1.18.5-win32-x64", "1.18.4", 1},
+		{"2023.2", "2024.1", -1},
+		{"8.0-rc-1", "8.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSelectOldVersions(t *testing.T) {
+	dirs := []versionedDir{
+		{path: "a1", key: "a", version: "1.0.0"},
+		{path: "a3", key: "a", version: "1.10.0"},
+		{path: "a2", key: "a", version: "1.2.0"},
+		{path: "b1", key: "b", version: "2.0.0"},
+	}
+
+	old := selectOldVersions(dirs, 2)
+	if len(old) != 1 || old[0].path != "a1" {
+		t.Fatalf("keep 2: got %+v, want only a1", old)
+	}
+
+	old = selectOldVersions(dirs, 0)
+	if len(old) != 2 {
+		t.Fatalf("keep 0 should be treated as 1, got %+v", old)
+	}
+	for _, d := range old {
+		if d.path == "a3" || d.path == "b1" {
+			t.Errorf("newest version %s must be kept", d.path)
+		}
+	}
+}
+
+func TestScanVSCodeExtensions(t *testing.T) {
+	home := t.TempDir()
+	extDir := filepath.Join(home, ".vscode", "extensions")
+	for _, name := range []string{
+		"ms-python.python-2023.20.0",
+		"ms-python.python-2024.2.1",
+		"ms-vscode.cpptools-1.18.5-win32-x64",
+		"ms-vscode.cpptools-1.19.0-win32-x64",
+		"golang.go-0.41.0",
+	} {
+		dir := filepath.Join(extDir, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items := scanVSCodeExtensions(home, 1, nil)
+	got := make(map[string]bool)
+	for _, item := range items {
+		got[filepath.Base(item.Path)] = true
+	}
+
+	want := []string{"ms-python.python-2023.20.0", "ms-vscode.cpptools-1.18.5-win32-x64"}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(items), len(want), items)
+	}
+	for _, name := range want {
+		if !got[name] {
+			t.Errorf("expected %s to be offered for cleanup", name)
+		}
+	}
+}
+
+func TestSplitRustup_UninstallsWholeToolchains(t *testing.T) {
+	toolchains := filepath.Join(`C:\Users\a\.rustup`, "toolchains")
+	items := []CleanItem{
+		{Path: filepath.Join(toolchains, "1.70.0-x86_64-pc-windows-msvc"), Size: 100, FileCount: 10, Description: rustToolchainsDescription, RiskLevel: "medium"},
+		{Path: filepath.Join(toolchains, "1.71.0-x86_64-pc-windows-msvc", "lib", "keep.rlib"), Size: 5, Description: rustToolchainsDescription},
+		{Path: `C:\a\gradle\w`, Size: 30, Description: "Gradle build cache"},
+	}
+
+	remaining, native := SplitRustup(items, &fakeRunner{})
+	if len(native) != 0 || len(remaining) != len(items) {
+		t.Fatalf("without rustup everything stays a file item, got %+v / %+v", remaining, native)
+	}
+
+	remaining, native = SplitRustup(items, &fakeRunner{installed: map[string]bool{"rustup": true}})
+	if len(native) != 1 {
+		t.Fatalf("expected one rustup cleanup, got %+v", native)
+	}
+	if got := native[0].Pruner.CommandLine(); got != "rustup toolchain uninstall 1.70.0-x86_64-pc-windows-msvc" {
+		t.Errorf("command = %q", got)
+	}
+	if native[0].Size != 100 || native[0].ItemCount != 10 || native[0].RiskLevel != "medium" {
+		t.Errorf("cleanup = %+v, want 100 bytes / 10 files at medium risk", native[0])
+	}
+	if len(remaining) != 2 {
+		t.Errorf("the partly whitelisted toolchain and Gradle should remain, got %+v", remaining)
+	}
+}
@@ -0,0 +1,280 @@
+package clean
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ─── Process Detection ───────────────────────────────────────────────────────
+// Deleting a browser's Cache or Code Cache while it is open causes sharing
+// violations and can corrupt the cache index, so caches of running
+// browsers are held back until the browser is closed.
+
+// ProcessInfo identifies a running process.
+type ProcessInfo struct {
+	PID      int32
+	Name     string // Image name (e.g. "chrome.exe").
+	Exe      string // Full executable path; "" if it cannot be read.
+	Username string // Owner as DOMAIN\user; "" if it cannot be read.
+}
+
+// ProcessLister lists running processes. It is an interface so browser
+// detection can be tested without real processes.
+type ProcessLister interface {
+	Processes() ([]ProcessInfo, error)
+}
+
+// gopsutilLister is the ProcessLister backed by gopsutil.
+type gopsutilLister struct{}
+
+func (gopsutilLister) Processes() ([]ProcessInfo, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]ProcessInfo, 0, len(procs))
+	for _, p := range procs {
+		name, nameErr := p.Name()
+		if nameErr != nil {
+			continue
+		}
+		// Exe and Username fail for other users' processes when not
+		// elevated; such processes are simply never closed.
+		exe, _ := p.Exe()
+		owner, _ := p.Username()
+		infos = append(infos, ProcessInfo{PID: p.Pid, Name: name, Exe: exe, Username: owner})
+	}
+	return infos, nil
+}
+
+// DefaultProcessLister lists the processes running on the real system.
+var DefaultProcessLister ProcessLister = gopsutilLister{}
+
+// runningImages returns the lower-cased image names of running processes.
+// A listing error yields an empty set.
+func runningImages(procs ProcessLister) map[string]bool {
+	running := make(map[string]bool)
+	infos, err := procs.Processes()
+	if err != nil {
+		return running
+	}
+	for _, p := range infos {
+		running[strings.ToLower(p.Name)] = true
+	}
+	return running
+}
+
+// markRunning sets InUse on every item whose browser is running.
+func markRunning(items []CleanItem, procs ProcessLister) {
+	running := runningImages(procs)
+	for i := range items {
+		items[i].InUse = running[strings.ToLower(browserImage(items[i].Browser))]
+	}
+}
+
+// browserImage returns the process image name of the named browser, or ""
+// if the browser is unknown. Browsers sharing an image (Chrome channels and
+// Chromium) cannot be told apart and are treated as running together.
+func browserImage(name string) string {
+	for _, b := range chromiumBrowsers("", "") {
+		if b.name == name {
+			return b.image
+		}
+	}
+	for _, b := range geckoBrowsers {
+		if b.name == name {
+			return b.image
+		}
+	}
+	return ""
+}
+
+// ─── Running Browser Handling ────────────────────────────────────────────────
+
+// InUseBrowsers returns the sorted names of browsers that own InUse items.
+func InUseBrowsers(results []ScanResult) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, r := range results {
+		for _, item := range r.Items {
+			if item.InUse && item.Browser != "" && !seen[item.Browser] {
+				seen[item.Browser] = true
+				names = append(names, item.Browser)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// browserExes returns the full executable paths the named browser runs
+// from: those registered under App Paths for its image and, for Chromium
+// browsers, the Application folder next to their User Data in
+// %LOCALAPPDATA% and Program Files. Paths are lower-cased.
+func browserExes(name string) []string {
+	local := os.Getenv("LOCALAPPDATA")
+	var exes []string
+	add := func(path string) {
+		if path != "" {
+			exes = append(exes, strings.ToLower(filepath.Clean(path)))
+		}
+	}
+
+	for _, b := range chromiumBrowsers(local, os.Getenv("APPDATA")) {
+		if b.name != name {
+			continue
+		}
+		for _, path := range registeredAppPaths(b.image) {
+			add(path)
+		}
+		if b.userData == "" || strings.ContainsAny(b.userData, "*?[") {
+			continue
+		}
+		install := filepath.Dir(b.userData)
+		add(filepath.Join(install, "Application", b.image))
+		if rel, err := filepath.Rel(local, install); err == nil {
+			for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+				if pf := os.Getenv(env); pf != "" {
+					add(filepath.Join(pf, rel, "Application", b.image))
+				}
+			}
+		}
+	}
+	for _, b := range geckoBrowsers {
+		if b.name == name {
+			for _, path := range registeredAppPaths(b.image) {
+				add(path)
+			}
+		}
+	}
+	return exes
+}
+
+// currentUsername returns the current user as DOMAIN\user, or "" if it
+// cannot be determined.
+func currentUsername() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// browserProcesses returns the PIDs of processes owned by owner whose
+// executable is one of the named browsers' known executables. Matching
+// on the full path keeps generic image names such as browser.exe from
+// catching unrelated programs.
+func browserProcesses(names []string, owner string, procs ProcessLister) []int32 {
+	if owner == "" {
+		return nil
+	}
+	exes := make(map[string]bool)
+	for _, name := range names {
+		for _, exe := range browserExes(name) {
+			exes[exe] = true
+		}
+	}
+	infos, err := procs.Processes()
+	if err != nil {
+		return nil
+	}
+	var pids []int32
+	for _, p := range infos {
+		if p.Exe == "" || !strings.EqualFold(p.Username, owner) {
+			continue
+		}
+		if exes[strings.ToLower(filepath.Clean(p.Exe))] {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
+
+// CloseBrowsers asks the current user's instances of the named browsers to
+// close gracefully (taskkill without /F, which sends a close request to
+// their windows) and waits up to timeout for them to exit. Only processes
+// running a known executable of the browser are asked to close.
+//
+// It returns the browsers whose image no longer runs at all; a browser
+// whose image is still in use, e.g. by another user or an unrecognised
+// install, keeps its caches held back.
+func CloseBrowsers(names []string, runner CommandRunner, procs ProcessLister, timeout time.Duration) []string {
+	owner := currentUsername()
+	pids := browserProcesses(names, owner, procs)
+	if len(pids) > 0 {
+		args := []string{"/FI", "USERNAME eq " + owner}
+		for _, pid := range pids {
+			args = append(args, "/PID", strconv.Itoa(int(pid)))
+		}
+		// Errors are expected when a process exits mid-request; the
+		// process list below is the source of truth.
+		_, _ = runner.Run("taskkill", args...)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for len(browserProcesses(names, owner, procs)) > 0 && time.Now().Before(deadline) {
+		time.Sleep(250 * time.Millisecond)
+	}
+
+	running := runningImages(procs)
+	var closed []string
+	for _, name := range names {
+		if !running[strings.ToLower(browserImage(name))] {
+			closed = append(closed, name)
+		}
+	}
+	return closed
+}
+
+// ReleaseInUse clears the InUse mark on items of the given browsers, e.g.
+// after CloseBrowsers closed them.
+func ReleaseInUse(results []ScanResult, browsers []string) []ScanResult {
+	closed := make(map[string]bool, len(browsers))
+	for _, name := range browsers {
+		closed[name] = true
+	}
+	for ri := range results {
+		for ii := range results[ri].Items {
+			if closed[results[ri].Items[ii].Browser] {
+				results[ri].Items[ii].InUse = false
+			}
+		}
+	}
+	return results
+}
+
+// SplitInUse separates items marked InUse from the rest. ready holds the
+// results that can be cleaned now; deferred holds the held-back items,
+// grouped under the same result names.
+func SplitInUse(results []ScanResult) (ready, deferred []ScanResult) {
+	for _, r := range results {
+		var free, held []CleanItem
+		for _, item := range r.Items {
+			if item.InUse {
+				held = append(held, item)
+			} else {
+				free = append(free, item)
+			}
+		}
+		if len(held) == 0 {
+			ready = append(ready, r)
+			continue
+		}
+		if len(free) > 0 {
+			kept := ItemsToResult(r.Category, free)
+			kept.User = r.User
+			ready = append(ready, kept)
+		}
+		later := ItemsToResult(r.Category, held)
+		later.User = r.User
+		deferred = append(deferred, later)
+	}
+	return ready, deferred
+}
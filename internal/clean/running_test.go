@@ -0,0 +1,132 @@
+package clean
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeLister returns its current process list.
+type fakeLister struct {
+	procs []ProcessInfo
+}
+
+func (f *fakeLister) Processes() ([]ProcessInfo, error) {
+	return f.procs, nil
+}
+
+// closingRunner records taskkill calls and closes the processes whose
+// PIDs it is given.
+type closingRunner struct {
+	lister *fakeLister
+	keep   string // image that ignores the close request
+	calls  [][]string
+}
+
+func (r *closingRunner) LookPath(name string) (string, error) { return name, nil }
+
+func (r *closingRunner) Run(name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	targets := make(map[string]bool)
+	for i, arg := range args {
+		if arg == "/PID" && i+1 < len(args) {
+			targets[args[i+1]] = true
+		}
+	}
+	var left []ProcessInfo
+	for _, p := range r.lister.procs {
+		if p.Name == r.keep || !targets[strconv.Itoa(int(p.PID))] {
+			left = append(left, p)
+		}
+	}
+	r.lister.procs = left
+	return nil, nil
+}
+
+// browserEnv points %LOCALAPPDATA% at a temp dir and returns the current
+// user, skipping the test if it cannot be determined.
+func browserEnv(t *testing.T) (local, owner string) {
+	t.Helper()
+	local = t.TempDir()
+	t.Setenv("LOCALAPPDATA", local)
+	t.Setenv("ProgramFiles", "")
+	t.Setenv("ProgramFiles(x86)", "")
+	owner = currentUsername()
+	if owner == "" {
+		t.Skip("current user unknown")
+	}
+	return local, owner
+}
+
+func TestSplitInUse(t *testing.T) {
+	results := []ScanResult{
+		ItemsToResult("Chrome cache", []CleanItem{
+			{Path: "a", Size: 10, Browser: "Chrome", InUse: true},
+			{Path: "b", Size: 5, Browser: "Chrome", InUse: true},
+		}),
+		ItemsToResult("Firefox cache", []CleanItem{
+			{Path: "c", Size: 7, Browser: "Firefox"},
+		}),
+	}
+
+	if got := InUseBrowsers(results); len(got) != 1 || got[0] != "Chrome" {
+		t.Fatalf("InUseBrowsers = %v, want [Chrome]", got)
+	}
+
+	ready, deferred := SplitInUse(results)
+	if len(ready) != 1 || ready[0].Category != "Firefox cache" {
+		t.Errorf("ready = %+v, want only Firefox cache", ready)
+	}
+	if len(deferred) != 1 || deferred[0].TotalSize != 15 {
+		t.Errorf("deferred = %+v, want Chrome cache of 15 bytes", deferred)
+	}
+
+	ready, deferred = SplitInUse(ReleaseInUse(results, []string{"Chrome"}))
+	if len(ready) != 2 || len(deferred) != 0 {
+		t.Errorf("after release: ready=%d deferred=%d, want 2 and 0", len(ready), len(deferred))
+	}
+}
+
+func TestCloseBrowsers(t *testing.T) {
+	local, owner := browserEnv(t)
+	lister := &fakeLister{procs: []ProcessInfo{
+		{PID: 1, Name: "chrome.exe", Exe: filepath.Join(local, "Google", "Chrome", "Application", "chrome.exe"), Username: owner},
+		{PID: 2, Name: "browser.exe", Exe: filepath.Join(local, "Yandex", "YandexBrowser", "Application", "browser.exe"), Username: owner},
+	}}
+	runner := &closingRunner{lister: lister, keep: "browser.exe"}
+
+	closed := CloseBrowsers([]string{"Chrome", "Yandex"}, runner, lister, 0)
+
+	if len(closed) != 1 || closed[0] != "Chrome" {
+		t.Errorf("closed = %v, want [Chrome]", closed)
+	}
+	for _, call := range runner.calls {
+		for _, arg := range call {
+			if arg == "/F" {
+				t.Errorf("browsers must be closed gracefully, got %v", call)
+			}
+		}
+	}
+}
+
+func TestCloseBrowsers_OnlyOwnInstallsOfCurrentUser(t *testing.T) {
+	local, owner := browserEnv(t)
+	yandex := filepath.Join(local, "Yandex", "YandexBrowser", "Application", "browser.exe")
+	lister := &fakeLister{procs: []ProcessInfo{
+		{PID: 1, Name: "browser.exe", Exe: yandex, Username: owner},
+		{PID: 2, Name: "browser.exe", Exe: filepath.Join(local, "Other", "browser.exe"), Username: owner},
+		{PID: 3, Name: "browser.exe", Exe: yandex, Username: "OTHER\\someone"},
+	}}
+	runner := &closingRunner{lister: lister}
+
+	closed := CloseBrowsers([]string{"Yandex"}, runner, lister, 0)
+
+	want := []string{"taskkill", "/FI", "USERNAME eq " + owner, "/PID", "1"}
+	if len(runner.calls) != 1 || strings.Join(runner.calls[0], "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %q, want only %q", runner.calls, want)
+	}
+	if len(closed) != 0 {
+		t.Errorf("closed = %v, want none while browser.exe still runs", closed)
+	}
+}
@@ -1,10 +1,9 @@
 package clean
 
 import (
+	"context"
 	"os"
 	"path/filepath"
-	"sort"
-	"sync"
 
 	"github.com/lakshaymaurya-felt/winmole/internal/config"
 	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
@@ -25,6 +24,34 @@ type CleanItem struct {
 
 	// Description is a human-readable label for the parent target.
 	Description string
+
+	// RiskLevel is inherited from the parent target (low, medium, high).
+	// Empty is treated as low.
+	RiskLevel string
+
+	// FileCount is the number of files an aggregate directory item stands
+	// for (see StreamOptions.CollapseThreshold). Zero for a single file.
+	FileCount int
+
+	// Browser is the browser owning the item, for browser caches.
+	Browser string
+
+	// InUse is true when the owning browser was running at scan time.
+	// Such items must not be deleted until the browser is closed.
+	InUse bool
+
+	// Root is the scanned directory the item was found under. Folders
+	// left empty beneath it may be pruned after cleanup (see
+	// PruneEmptyDirs); the root itself is kept. Empty for single files.
+	Root string
+}
+
+// Files returns the number of files the item represents.
+func (i CleanItem) Files() int {
+	if i.FileCount > 0 {
+		return i.FileCount
+	}
+	return 1
 }
 
 // ScanResult holds the aggregated scan output for a single clean target.
@@ -32,14 +59,48 @@ type ScanResult struct {
 	// Category is the target name (e.g. "ChromeCache", "NpmCache").
 	Category string
 
-	// Items is the list of discovered cleanable files/directories.
+	// Items is the list of discovered cleanable files/directories. Empty
+	// for a result summarized from a stream; see Source and EachItem.
 	Items []CleanItem
 
+	// Source, when set, streams the result's items again in place of
+	// Items (see SummarizeStream).
+	Source *StreamSource
+
 	// TotalSize is the sum of all item sizes in bytes.
 	TotalSize int64
 
-	// ItemCount is the number of items discovered.
+	// ItemCount is the number of files discovered (aggregate items count
+	// every file they stand for).
 	ItemCount int
+
+	// RiskLevel is the highest risk level among the items.
+	RiskLevel string
+
+	// User is the profile the result belongs to when scanning all users
+	// (see core.UserProfiles). Empty for the current user and system-wide
+	// targets.
+	User string
+}
+
+// Group returns the high-level category (user, browser, dev, system) of
+// the result.
+func (r ScanResult) Group() string {
+	if r.Source != nil {
+		return r.Source.Target.Category
+	}
+	if len(r.Items) > 0 {
+		return r.Items[0].Category
+	}
+	return ""
+}
+
+// Key uniquely identifies the result across users, e.g. for goal plans.
+func (r ScanResult) Key() string {
+	if r.User == "" {
+		return r.Category
+	}
+	return r.User + `\` + r.Category
 }
 
 // ─── Parallel Scan Engine ────────────────────────────────────────────────────
@@ -47,96 +108,39 @@ type ScanResult struct {
 // ScanAll scans all provided targets in parallel, returning results for each
 // target that has cleanable items. Targets requiring admin privileges are
 // skipped when isAdmin is false. Whitelisted paths are excluded.
+//
+// ScanAll holds every item in memory; use StreamTargets for huge caches.
 func ScanAll(targets []config.CleanTarget, wl *whitelist.Whitelist, isAdmin bool) []ScanResult {
-	var (
-		mu      sync.Mutex
-		wg      sync.WaitGroup
-		results []ScanResult
-	)
-
-	for _, t := range targets {
-		// Skip admin-required targets if not elevated.
-		if t.RequiresAdmin && !isAdmin {
-			continue
-		}
-
-		// RecycleBin has no filesystem paths; handled via Shell API separately.
-		if t.Name == "RecycleBin" {
-			continue
-		}
-
-		wg.Add(1)
-		go func(target config.CleanTarget) {
-			defer wg.Done()
-
-			items := scanTarget(target, wl)
-			if len(items) == 0 {
-				return
-			}
-
-			result := ItemsToResult(target.Name, items)
-
-			mu.Lock()
-			results = append(results, result)
-			mu.Unlock()
-		}(t)
-	}
-
-	wg.Wait()
-
-	// Sort results by category name for stable output.
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Category < results[j].Category
-	})
+	events := StreamTargets(context.Background(), targets, wl, isAdmin, StreamOptions{})
+	return CollectStream(events, nil)
+}
 
-	return results
+// hasDedicatedScan reports whether a config target is scanned and cleaned
+// by its own step rather than the generic path walker. RecycleBin has no
+// filesystem paths (Shell API); MemoryDumps and WindowsOld would otherwise
+// be counted twice; SteamCache paths depend on the Steam library layout.
+func hasDedicatedScan(name string) bool {
+	return name == "RecycleBin" || name == "MemoryDumps" || name == "WindowsOld" ||
+		name == "SteamCache"
 }
 
 // ─── Single-Target Scanning ──────────────────────────────────────────────────
 
-// scanTarget scans a single CleanTarget by resolving environment variables
-// and glob patterns in its paths.
-func scanTarget(target config.CleanTarget, wl *whitelist.Whitelist) []CleanItem {
-	var items []CleanItem
-
-	for _, rawPath := range target.Paths {
-		// Expand environment variables.
-		expanded := os.ExpandEnv(rawPath)
-
-		// Attempt glob expansion for wildcard patterns.
-		matches, err := filepath.Glob(expanded)
-		if err != nil || len(matches) == 0 {
-			// If glob fails or returns nothing, try the literal path.
-			matches = []string{expanded}
-		}
-
-		for _, path := range matches {
-			path = filepath.Clean(path)
-
-			// Skip whitelisted paths.
-			if wl != nil && wl.IsWhitelisted(path) {
-				continue
-			}
-
-			info, statErr := os.Lstat(path)
-			if statErr != nil {
-				continue // Path doesn't exist or is inaccessible.
-			}
-
-			if info.IsDir() {
-				dirItems := scanDirectory(path, target.Category, target.Description, wl)
-				items = append(items, dirItems...)
-			} else {
-				items = append(items, CleanItem{
-					Path:        path,
-					Size:        info.Size(),
-					Category:    target.Category,
-					Description: target.Description,
-				})
-			}
-		}
+// targetRisk returns the configured risk level of the named target,
+// defaulting to low for unknown targets.
+func targetRisk(name string) string {
+	if t, ok := config.GetTarget(name); ok {
+		return t.RiskLevel
 	}
+	return config.RiskLow
+}
 
+// withRisk stamps every item with the given risk level and returns the
+// same slice for chaining.
+func withRisk(items []CleanItem, level string) []CleanItem {
+	for i := range items {
+		items[i].RiskLevel = level
+	}
 	return items
 }
 
@@ -167,6 +171,7 @@ func scanDirectory(dir, category, description string, wl *whitelist.Whitelist) [
 			Size:        info.Size(),
 			Category:    category,
 			Description: description,
+			Root:        dir,
 		})
 		return nil
 	})
@@ -177,18 +182,39 @@ func scanDirectory(dir, category, description string, wl *whitelist.Whitelist) [
 // ─── Aggregation Helpers ─────────────────────────────────────────────────────
 
 // ItemsToResult converts a slice of CleanItems into a ScanResult with
-// the given name and pre-calculated totals.
+// the given name and pre-calculated totals. The result's risk level is the
+// highest risk level found among the items.
 func ItemsToResult(name string, items []CleanItem) ScanResult {
 	var totalSize int64
+	var files int
+	risk := config.RiskLow
 	for _, item := range items {
 		totalSize += item.Size
+		files += item.Files()
+		if config.RiskRank(item.RiskLevel) > config.RiskRank(risk) {
+			risk = item.RiskLevel
+		}
 	}
 	return ScanResult{
 		Category:  name,
 		Items:     items,
 		TotalSize: totalSize,
-		ItemCount: len(items),
+		ItemCount: files,
+		RiskLevel: risk,
+	}
+}
+
+// FilterByMaxRisk returns only the results whose risk level does not
+// exceed maxRisk.
+func FilterByMaxRisk(results []ScanResult, maxRisk string) []ScanResult {
+	ceiling := config.RiskRank(maxRisk)
+	filtered := make([]ScanResult, 0, len(results))
+	for _, r := range results {
+		if config.RiskRank(r.RiskLevel) <= ceiling {
+			filtered = append(filtered, r)
+		}
 	}
+	return filtered
 }
 
 // GroupByCategory aggregates scan results by the high-level category of
@@ -196,8 +222,7 @@ func ItemsToResult(name string, items []CleanItem) ScanResult {
 func GroupByCategory(results []ScanResult) map[string][]ScanResult {
 	groups := make(map[string][]ScanResult)
 	for _, r := range results {
-		if len(r.Items) > 0 {
-			cat := r.Items[0].Category
+		if cat := r.Group(); cat != "" {
 			groups[cat] = append(groups[cat], r)
 		}
 	}
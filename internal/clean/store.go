@@ -0,0 +1,144 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Microsoft Store App Caches ──────────────────────────────────────────────
+// UWP and MSIX apps keep per-user data under
+// %LOCALAPPDATA%\Packages\<PackageFamilyName>. Only the cache and temp
+// folders are touched; LocalState, RoamingState and Settings hold app data.
+
+// storeCacheDef is a disposable folder within a package directory.
+type storeCacheDef struct {
+	subdir    string
+	riskLevel string
+}
+
+// storeCacheDefs lists the cleanable folders of a package. LocalCache is
+// medium risk: some apps keep rebuildable but expensive data there.
+var storeCacheDefs = []storeCacheDef{
+	{subdir: "TempState", riskLevel: config.RiskLow},
+	{subdir: filepath.Join("AC", "INetCache"), riskLevel: config.RiskLow},
+	{subdir: filepath.Join("AC", "Temp"), riskLevel: config.RiskLow},
+	{subdir: "LocalCache", riskLevel: config.RiskMedium},
+}
+
+// storeCacheExclusions are entries within LocalCache that hold user data
+// rather than caches: the Store Python's pip --user packages, and the
+// Local and Roaming folders MSIX apps get as their redirected %LOCALAPPDATA%
+// and %APPDATA% (e.g. Arc's whole browser profile).
+var storeCacheExclusions = map[string]bool{
+	"local-packages": true,
+	"local":          true,
+	"roaming":        true,
+}
+
+// storeFriendlyNames maps package names whose identifiers do not read
+// well to their display names.
+var storeFriendlyNames = map[string]string{
+	"MSTeams":                               "Microsoft Teams",
+	"MicrosoftTeams":                        "Microsoft Teams (classic)",
+	"Microsoft.WindowsStore":                "Microsoft Store",
+	"Microsoft.ZuneMusic":                   "Media Player",
+	"Microsoft.ZuneVideo":                   "Movies & TV",
+	"Microsoft.Windows.Photos":              "Photos",
+	"Microsoft.YourPhone":                   "Phone Link",
+	"Microsoft.GamingApp":                   "Xbox",
+	"Microsoft.XboxGamingOverlay":           "Xbox Game Bar",
+	"Microsoft.BingWeather":                 "Weather",
+	"Microsoft.BingNews":                    "News",
+	"Microsoft.OutlookForWindows":           "Outlook",
+	"Microsoft.Windows.Search":              "Windows Search",
+	"MicrosoftWindows.Client.WebExperience": "Widgets",
+	"SpotifyAB.SpotifyMusic":                "Spotify",
+	"5319275A.WhatsAppDesktop":              "WhatsApp",
+	"4DF9E0F8.Netflix":                      "Netflix",
+	"Microsoft.MicrosoftStickyNotes":        "Sticky Notes",
+	"Microsoft.ScreenSketch":                "Snipping Tool",
+}
+
+// StoreAppName returns a display name for a package family name such as
+// "Microsoft.WindowsTerminal_8wekyb3d8bbwe". Known packages use their
+// product name; others drop the publisher hash and prefix and split the
+// remaining identifier into words ("Windows Terminal").
+func StoreAppName(familyName string) string {
+	name, _, _ := strings.Cut(familyName, "_")
+	if friendly, ok := storeFriendlyNames[name]; ok {
+		return friendly
+	}
+
+	// Drop the publisher prefix ("Microsoft.", "5319275A.") when one is present.
+	if i := strings.LastIndex(name, "."); i >= 0 && i < len(name)-1 {
+		name = name[i+1:]
+	}
+
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ScanStoreAppCaches scans the cache and temp folders of every installed
+// Store app package for the current user.
+func ScanStoreAppCaches(wl *whitelist.Whitelist) []CleanItem {
+	return scanStorePackages(filepath.Join(os.Getenv("LOCALAPPDATA"), "Packages"), wl)
+}
+
+// scanStorePackages scans every package directory under packagesDir.
+// Items use the "store" category and one description per app.
+func scanStorePackages(packagesDir string, wl *whitelist.Whitelist) []CleanItem {
+	var items []CleanItem
+	for _, family := range listSubdirs(packagesDir) {
+		pkgDir := filepath.Join(packagesDir, family)
+		if wl != nil && wl.IsWhitelisted(pkgDir) {
+			continue
+		}
+
+		desc := StoreAppName(family) + " cache"
+		for _, def := range storeCacheDefs {
+			dir := filepath.Join(pkgDir, def.subdir)
+			if wl != nil && wl.IsWhitelisted(dir) {
+				continue
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+
+			for _, e := range entries {
+				if def.subdir == "LocalCache" && storeCacheExclusions[strings.ToLower(e.Name())] {
+					continue
+				}
+				path := filepath.Join(dir, e.Name())
+				if wl != nil && wl.IsWhitelisted(path) {
+					continue
+				}
+
+				var found []CleanItem
+				if e.IsDir() {
+					found = scanDirectory(path, "store", desc, wl)
+					for i := range found {
+						found[i].Root = dir
+					}
+				} else if info, infoErr := e.Info(); infoErr == nil {
+					found = []CleanItem{{Path: path, Size: info.Size(), Category: "store", Description: desc}}
+				}
+				items = append(items, withRisk(found, def.riskLevel)...)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,61 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+)
+
+func TestStoreAppName(t *testing.T) {
+	tests := map[string]string{
+		"Microsoft.WindowsTerminal_8wekyb3d8bbwe":      "Windows Terminal",
+		"SpotifyAB.SpotifyMusic_zpdnekdrzrea0":         "Spotify",
+		"MSTeams_8wekyb3d8bbwe":                        "Microsoft Teams",
+		"Microsoft.XboxIdentityProvider_8wekyb3d8bbwe": "Xbox Identity Provider",
+		"Clipchamp.Clipchamp_yxz26nhyzhsrt":            "Clipchamp",
+	}
+	for family, want := range tests {
+		if got := StoreAppName(family); got != want {
+			t.Errorf("StoreAppName(%q) = %q, want %q", family, got, want)
+		}
+	}
+}
+
+func TestScanStorePackages(t *testing.T) {
+	packages := t.TempDir()
+	pkg := filepath.Join(packages, "PythonSoftwareFoundation.Python.3.12_qbz5n2kfra8p0")
+	files := map[string]string{
+		filepath.Join("TempState", "tmp.dat"):                    "x",
+		filepath.Join("AC", "INetCache", "IE", "page.htm"):       "xx",
+		filepath.Join("LocalCache", "shaders", "cache.bin"):      "xxx",
+		filepath.Join("LocalCache", "local-packages", "site.py"): "keep",
+		filepath.Join("LocalCache", "Local", "Arc", "Cookies"):   "keep",
+		filepath.Join("LocalCache", "Roaming", "app.json"):       "keep",
+		filepath.Join("LocalState", "settings.dat"):              "keep",
+	}
+	for rel, content := range files {
+		path := filepath.Join(pkg, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items := scanStorePackages(packages, nil)
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3: %+v", len(items), items)
+	}
+	for _, item := range items {
+		name := filepath.Base(item.Path)
+		if name == "site.py" || name == "settings.dat" || name == "Cookies" || name == "app.json" {
+			t.Errorf("%s must not be cleaned", item.Path)
+		}
+		if name == "cache.bin" && item.RiskLevel != config.RiskMedium {
+			t.Errorf("LocalCache items should be medium risk, got %q", item.RiskLevel)
+		}
+	}
+}
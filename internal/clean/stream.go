@@ -0,0 +1,479 @@
+package clean
+
+import (
+	"context"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// ─── Streaming Scan API ──────────────────────────────────────────────────────
+// ScanAll materializes every file of every target before returning. On
+// build servers with millions of cached files that balloons memory and
+// delays the first output, so StreamTargets emits items over a channel as
+// they are found, with running totals, and can collapse large directories
+// into a single aggregate item.
+
+// StreamOptions controls how StreamTargets walks and reports targets.
+type StreamOptions struct {
+	// CollapseThreshold collapses any directory directly under a target
+	// root that holds at least this many files into one aggregate item.
+	// Zero disables collapsing (one item per file).
+	CollapseThreshold int
+
+	// Buffer is the channel buffer size. Defaults to 256.
+	Buffer int
+}
+
+// ScanEvent is a single item emitted by StreamTargets.
+type ScanEvent struct {
+	// Target is the name of the clean target the item belongs to.
+	Target string
+
+	// Item is a file, or a whole directory when Item.FileCount > 0.
+	Item CleanItem
+
+	// RunningSize is the total bytes emitted so far, including this item.
+	RunningSize int64
+
+	// RunningFiles is the total files emitted so far, including this item.
+	RunningFiles int64
+}
+
+// StreamTargets scans the given targets in parallel and streams their items
+// over the returned channel, which is closed once every target has been
+// walked or ctx is cancelled. Admin-only targets are skipped when isAdmin
+// is false and whitelisted paths are never emitted.
+func StreamTargets(ctx context.Context, targets []config.CleanTarget, wl *whitelist.Whitelist, isAdmin bool, opts StreamOptions) <-chan ScanEvent {
+	if opts.Buffer <= 0 {
+		opts.Buffer = 256
+	}
+
+	raw := make(chan ScanEvent, opts.Buffer)
+	out := make(chan ScanEvent, opts.Buffer)
+
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		if t.RequiresAdmin && !isAdmin {
+			continue
+		}
+		if hasDedicatedScan(t.Name) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(target config.CleanTarget) {
+			defer wg.Done()
+			streamTarget(ctx, target, wl, opts, raw)
+		}(t)
+	}
+
+	go func() {
+		wg.Wait()
+		close(raw)
+	}()
+
+	// Single forwarder keeps running totals consistent across workers.
+	go func() {
+		defer close(out)
+		var size, files int64
+		for ev := range raw {
+			size += ev.Item.Size
+			files += int64(ev.Item.Files())
+			ev.RunningSize = size
+			ev.RunningFiles = files
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				// Drain so workers can exit.
+				for range raw {
+				}
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// CollectStream drains a ScanEvent stream into per-target ScanResults,
+// sorted by target name. onEvent, if non-nil, is called for every event
+// (e.g. to update a progress display) before it is collected.
+func CollectStream(events <-chan ScanEvent, onEvent func(ScanEvent)) []ScanResult {
+	byTarget := make(map[string][]CleanItem)
+	for ev := range events {
+		if onEvent != nil {
+			onEvent(ev)
+		}
+		byTarget[ev.Target] = append(byTarget[ev.Target], ev.Item)
+	}
+
+	results := make([]ScanResult, 0, len(byTarget))
+	for name, items := range byTarget {
+		results = append(results, ItemsToResult(name, items))
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Category < results[j].Category
+	})
+	return results
+}
+
+// ─── Streamed Results ────────────────────────────────────────────────────────
+// For display and cleanup, streamed targets are kept as totals only: the
+// scan sums each target as items arrive, and cleaning streams the target
+// again and acts on each item as it comes (see ScanResult.EachItem).
+// Only a fingerprint of each previewed item is kept, so cleaning never
+// touches files that appeared after the user confirmed the preview.
+
+// StreamSource is how to stream a summarized result's items again.
+type StreamSource struct {
+	Target    config.CleanTarget
+	Whitelist *whitelist.Whitelist
+	Options   StreamOptions
+
+	// Roots are the scanned directories items were found under, for
+	// PruneRoots.
+	Roots []string
+
+	// previewed maps the fingerprint of every summarized item's path to
+	// its file count.
+	previewed map[uint64]int
+}
+
+// fingerprint returns a compact, case-insensitive key for path.
+func fingerprint(path string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.ToLower(path)))
+	return h.Sum64()
+}
+
+// SummarizeStream streams targets and returns one result per target that
+// had items, holding totals and a Source instead of the items. onEvent,
+// if non-nil, is called for every event (e.g. to update a progress
+// display).
+func SummarizeStream(ctx context.Context, targets []config.CleanTarget, wl *whitelist.Whitelist, isAdmin bool, opts StreamOptions, onEvent func(ScanEvent)) []ScanResult {
+	byName := make(map[string]config.CleanTarget, len(targets))
+	for _, t := range targets {
+		byName[t.Name] = t
+	}
+
+	totals := make(map[string]*ScanResult)
+	roots := make(map[string]map[string]bool)
+	for ev := range StreamTargets(ctx, targets, wl, isAdmin, opts) {
+		if onEvent != nil {
+			onEvent(ev)
+		}
+		r := totals[ev.Target]
+		if r == nil {
+			t := byName[ev.Target]
+			r = &ScanResult{
+				Category:  t.Name,
+				RiskLevel: riskOrLow(t.RiskLevel),
+				Source: &StreamSource{
+					Target:    t,
+					Whitelist: wl,
+					Options:   opts,
+					previewed: make(map[uint64]int),
+				},
+			}
+			totals[ev.Target] = r
+			roots[ev.Target] = make(map[string]bool)
+		}
+		r.TotalSize += ev.Item.Size
+		r.ItemCount += ev.Item.Files()
+		r.Source.previewed[fingerprint(ev.Item.Path)] = ev.Item.Files()
+		if root := ev.Item.Root; root != "" && !roots[ev.Target][root] {
+			roots[ev.Target][root] = true
+			r.Source.Roots = append(r.Source.Roots, root)
+		}
+	}
+
+	results := make([]ScanResult, 0, len(totals))
+	for _, r := range totals {
+		sort.Strings(r.Source.Roots)
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Category < results[j].Category
+	})
+	return results
+}
+
+// riskOrLow returns level, or low if it is unset.
+func riskOrLow(level string) string {
+	if level == "" {
+		return config.RiskLow
+	}
+	return level
+}
+
+// EachItem calls fn for every item of r, in order, until fn returns
+// false. A summarized result (see SummarizeStream) is streamed again from
+// disk and only items that were previewed are passed on: files that
+// vanished since the scan are skipped, and files that appeared since, or
+// collapsed folders that gained files, are held back. It returns the
+// number of files held back.
+func (r ScanResult) EachItem(fn func(CleanItem) bool) (heldBack int) {
+	if r.Source == nil {
+		for _, item := range r.Items {
+			if !fn(item) {
+				return 0
+			}
+		}
+		return 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Admin rights were checked when the result was summarized.
+	events := StreamTargets(ctx, []config.CleanTarget{r.Source.Target}, r.Source.Whitelist, true, r.Source.Options)
+	for ev := range events {
+		files, ok := r.Source.previewed[fingerprint(ev.Item.Path)]
+		if !ok || ev.Item.Files() > files {
+			heldBack += ev.Item.Files()
+			continue
+		}
+		if !fn(ev.Item) {
+			cancel()
+			for range events {
+			}
+			return heldBack
+		}
+	}
+	return heldBack
+}
+
+// streamTarget resolves environment variables and glob patterns in a
+// target's paths and sends each discovered item to events.
+func streamTarget(ctx context.Context, target config.CleanTarget, wl *whitelist.Whitelist, opts StreamOptions, events chan<- ScanEvent) {
+	emit := func(item CleanItem) bool {
+		item.Category = target.Category
+		item.Description = target.Description
+		item.RiskLevel = target.RiskLevel
+		select {
+		case events <- ScanEvent{Target: target.Name, Item: item}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for _, rawPath := range target.Paths {
+		expanded := os.ExpandEnv(rawPath)
+
+		matches, err := filepath.Glob(expanded)
+		if err != nil || len(matches) == 0 {
+			matches = []string{expanded}
+		}
+
+		for _, path := range matches {
+			path = filepath.Clean(path)
+
+			if wl != nil && wl.IsWhitelisted(path) {
+				continue
+			}
+
+			info, statErr := os.Lstat(path)
+			if statErr != nil {
+				continue
+			}
+
+			if !info.IsDir() {
+				if !emit(CleanItem{Path: path, Size: info.Size()}) {
+					return
+				}
+				continue
+			}
+
+			root := path
+			emitUnder := func(item CleanItem) bool {
+				item.Root = root
+				return emit(item)
+			}
+			if !streamDirectory(ctx, path, wl, opts.CollapseThreshold, emitUnder) {
+				return
+			}
+		}
+	}
+}
+
+// streamDirectory emits the files under dir. When collapse > 0, each
+// subdirectory directly under dir holding at least collapse files (and no
+// whitelisted entries) is emitted as one aggregate item instead. Returns
+// false if emission was cancelled.
+func streamDirectory(ctx context.Context, dir string, wl *whitelist.Whitelist, collapse int, emit func(CleanItem) bool) bool {
+	if collapse <= 0 {
+		return walkFiles(dir, wl, emit)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true
+	}
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if wl != nil && wl.IsWhitelisted(path) {
+			continue
+		}
+
+		if !e.IsDir() {
+			info, infoErr := e.Info()
+			if infoErr != nil {
+				continue
+			}
+			if !emit(CleanItem{Path: path, Size: info.Size()}) {
+				return false
+			}
+			continue
+		}
+
+		if !streamSubdir(path, wl, collapse, emit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// streamSubdir emits dir as one aggregate item if it holds at least
+// collapse files and nothing whitelisted, and its files one by one
+// otherwise. Sizes are summed in the same walk; at most collapse files
+// are held while deciding. Only a whitelisted entry found after the
+// threshold was reached needs a second walk.
+func streamSubdir(dir string, wl *whitelist.Whitelist, collapse int, emit func(CleanItem) bool) bool {
+	var (
+		held      []CleanItem // files seen while below the threshold
+		size      int64
+		files     int
+		collapsed bool // threshold reached; held was dropped
+		protected bool // a whitelisted entry was found
+		rewalk    bool
+		ok        = true
+	)
+
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if wl != nil && wl.IsWhitelisted(path) {
+			if collapsed {
+				rewalk = true
+				return filepath.SkipAll
+			}
+			if !protected {
+				// No aggregate now; release what was held back.
+				protected = true
+				for _, item := range held {
+					if !emit(item) {
+						ok = false
+						return filepath.SkipAll
+					}
+				}
+				held = nil
+			}
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return nil
+		}
+
+		item := CleanItem{Path: path, Size: info.Size()}
+		switch {
+		case protected:
+			if !emit(item) {
+				ok = false
+				return filepath.SkipAll
+			}
+		case !collapsed:
+			held = append(held, item)
+			if len(held) >= collapse {
+				held, collapsed = nil, true
+			}
+		}
+		size += item.Size
+		files++
+		return nil
+	})
+
+	switch {
+	case !ok:
+		return false
+	case rewalk:
+		return walkFiles(dir, wl, emit)
+	case collapsed:
+		return emit(CleanItem{Path: dir, Size: size, FileCount: files})
+	}
+	for _, item := range held {
+		if !emit(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkFiles emits every non-whitelisted file under dir, one item each.
+func walkFiles(dir string, wl *whitelist.Whitelist, emit func(CleanItem) bool) bool {
+	ok := true
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if wl != nil && wl.IsWhitelisted(path) {
+			return nil
+		}
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return nil
+		}
+		if !emit(CleanItem{Path: path, Size: info.Size()}) {
+			ok = false
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return ok
+}
+
+// measureDir returns the total size and file count under dir without
+// retaining any per-file data. protected is true if any entry inside is
+// whitelisted, in which case the directory must not be deleted as a whole.
+func measureDir(dir string, wl *whitelist.Whitelist) (size int64, files int, protected bool) {
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if wl != nil && wl.IsWhitelisted(path) {
+			protected = true
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return nil
+		}
+		size += info.Size()
+		files++
+		return nil
+	})
+	return size, files, protected
+}
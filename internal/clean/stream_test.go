@@ -0,0 +1,189 @@
+package clean
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
+	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
+)
+
+// writeFiles creates n files of size bytes each under dir.
+func writeFiles(t *testing.T, dir string, n, size int) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		name := filepath.Join(dir, "f"+string(rune('a'+i)))
+		if err := os.WriteFile(name, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStreamTargets_CollapsesLargeDirectories(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "big"), 5, 10)
+	writeFiles(t, filepath.Join(root, "small"), 1, 7)
+	writeFiles(t, root, 1, 3)
+
+	target := config.CleanTarget{
+		Name:      "Test",
+		Paths:     []string{root},
+		Category:  "user",
+		RiskLevel: "low",
+	}
+
+	events := StreamTargets(context.Background(), []config.CleanTarget{target}, nil, false,
+		StreamOptions{CollapseThreshold: 3})
+
+	var last ScanEvent
+	var items []CleanItem
+	for ev := range events {
+		items = append(items, ev.Item)
+		last = ev
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items (1 aggregate + 2 files), got %d: %+v", len(items), items)
+	}
+	if last.RunningFiles != 7 || last.RunningSize != 5*10+7+3 {
+		t.Errorf("running totals = %d files / %d bytes, want 7 / 60", last.RunningFiles, last.RunningSize)
+	}
+
+	var aggregate *CleanItem
+	for i := range items {
+		if items[i].FileCount > 0 {
+			aggregate = &items[i]
+		}
+	}
+	if aggregate == nil || filepath.Base(aggregate.Path) != "big" || aggregate.FileCount != 5 {
+		t.Errorf("expected 'big' to be collapsed into one aggregate item, got %+v", aggregate)
+	}
+}
+
+func TestCollectStream_MatchesItemTotals(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "sub"), 4, 5)
+
+	target := config.CleanTarget{Name: "Test", Paths: []string{root}, Category: "user", RiskLevel: "medium"}
+	results := CollectStream(StreamTargets(context.Background(), []config.CleanTarget{target}, nil, false, StreamOptions{}), nil)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if r.ItemCount != 4 || r.TotalSize != 20 || r.RiskLevel != "medium" {
+		t.Errorf("unexpected result: count=%d size=%d risk=%q", r.ItemCount, r.TotalSize, r.RiskLevel)
+	}
+}
+
+func TestStreamTargets_CollapseSkipsProtectedDirectories(t *testing.T) {
+	// Files are walked fa..fe; with a threshold of 3 a whitelisted fb is
+	// found while files are still held back, fe only after collapsing.
+	for _, protected := range []string{"fb", "fe"} {
+		root := t.TempDir()
+		writeFiles(t, filepath.Join(root, "big"), 5, 10)
+
+		wl, err := whitelist.Load(filepath.Join(t.TempDir(), "whitelist.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := wl.Add(filepath.Join(root, "big", protected)); err != nil {
+			t.Fatal(err)
+		}
+
+		target := config.CleanTarget{Name: "Test", Paths: []string{root}, Category: "user"}
+		var items []CleanItem
+		for ev := range StreamTargets(context.Background(), []config.CleanTarget{target}, wl, false,
+			StreamOptions{CollapseThreshold: 3}) {
+			items = append(items, ev.Item)
+		}
+
+		if len(items) != 4 {
+			t.Fatalf("%s whitelisted: expected 4 single files, got %+v", protected, items)
+		}
+		for _, item := range items {
+			if item.FileCount > 0 || filepath.Base(item.Path) == protected {
+				t.Errorf("%s whitelisted: unexpected item %+v", protected, item)
+			}
+		}
+	}
+}
+
+func TestSummarizeStream_EachItemStreamsAgain(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "sub"), 4, 5)
+
+	target := config.CleanTarget{Name: "Test", Paths: []string{root}, Category: "dev", RiskLevel: "medium"}
+	results := SummarizeStream(context.Background(), []config.CleanTarget{target}, nil, false, StreamOptions{}, nil)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if len(r.Items) != 0 || r.Source == nil {
+		t.Fatalf("summary should hold no items, got %d", len(r.Items))
+	}
+	if r.ItemCount != 4 || r.TotalSize != 20 || r.RiskLevel != "medium" || r.Group() != "dev" {
+		t.Errorf("unexpected summary: count=%d size=%d risk=%q group=%q", r.ItemCount, r.TotalSize, r.RiskLevel, r.Group())
+	}
+	if roots := PruneRoots(results); len(roots) != 1 || roots[0] != filepath.Clean(root) {
+		t.Errorf("PruneRoots = %v, want [%s]", roots, root)
+	}
+
+	var size int64
+	r.EachItem(func(item CleanItem) bool {
+		size += item.Size
+		return true
+	})
+	if size != r.TotalSize {
+		t.Errorf("streamed %d bytes, summary has %d", size, r.TotalSize)
+	}
+
+	n := 0
+	r.EachItem(func(CleanItem) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("EachItem continued after fn returned false: %d calls", n)
+	}
+}
+
+func TestSummarizeStream_EachItemHoldsBackNewFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "loose"), 2, 5)
+	writeFiles(t, filepath.Join(root, "big"), 4, 10)
+
+	target := config.CleanTarget{Name: "Test", Paths: []string{root}, Category: "dev"}
+	opts := StreamOptions{CollapseThreshold: 3}
+	results := SummarizeStream(context.Background(), []config.CleanTarget{target}, nil, false, opts, nil)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	// A new loose file, and a new file inside the collapsed folder, appear
+	// after the preview.
+	if err := os.WriteFile(filepath.Join(root, "new.tmp"), []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "big", "late"), []byte("late"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var seen []string
+	heldBack := results[0].EachItem(func(item CleanItem) bool {
+		seen = append(seen, item.Path)
+		return true
+	})
+	if heldBack != 6 {
+		t.Errorf("held back %d files, want 6 (new.tmp and the grown folder's 5)", heldBack)
+	}
+	if len(seen) != 2 {
+		t.Errorf("only the 2 previewed loose files should be passed on, got %v", seen)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
 	"github.com/lakshaymaurya-felt/winmole/internal/core"
 	"github.com/lakshaymaurya-felt/winmole/internal/ui"
 	"github.com/lakshaymaurya-felt/winmole/pkg/whitelist"
@@ -30,35 +31,35 @@ func ScanSystemCaches(wl *whitelist.Whitelist) []CleanItem {
 	targets := []systemTarget{
 		{
 			name:        "WindowsTemp",
-			paths:       []string{`C:\Windows\Temp`},
+			paths:       []string{config.Rebase(`C:\Windows\Temp`)},
 			description: "System temporary files",
 		},
 		{
 			name:        "WUCache",
-			paths:       []string{`C:\Windows\SoftwareDistribution\Download`},
+			paths:       []string{config.Rebase(`C:\Windows\SoftwareDistribution\Download`)},
 			description: "Windows Update download cache",
 		},
 		{
 			name:        "CBSLogs",
-			paths:       []string{`C:\Windows\Logs\CBS`},
+			paths:       []string{config.Rebase(`C:\Windows\Logs\CBS`)},
 			description: "CBS servicing logs",
 		},
 		{
 			name:        "DISMLogs",
-			paths:       []string{`C:\Windows\Logs\DISM`},
+			paths:       []string{config.Rebase(`C:\Windows\Logs\DISM`)},
 			description: "DISM operation logs",
 		},
 		{
 			name: "WERReports",
 			paths: []string{
-				`C:\ProgramData\Microsoft\Windows\WER\ReportQueue`,
-				`C:\ProgramData\Microsoft\Windows\WER\Temp`,
+				config.Rebase(`C:\ProgramData\Microsoft\Windows\WER\ReportQueue`),
+				config.Rebase(`C:\ProgramData\Microsoft\Windows\WER\Temp`),
 			},
 			description: "Windows Error Reporting",
 		},
 		{
 			name:        "DeliveryOptimization",
-			paths:       []string{`C:\Windows\SoftwareDistribution\DeliveryOptimization`},
+			paths:       []string{config.Rebase(`C:\Windows\SoftwareDistribution\DeliveryOptimization`)},
 			description: "Delivery Optimization cache",
 		},
 	}
@@ -92,7 +93,7 @@ func ScanMemoryDumps() []CleanItem {
 	var items []CleanItem
 
 	// Full memory dump.
-	memDump := `C:\Windows\MEMORY.DMP`
+	memDump := config.Rebase(`C:\Windows\MEMORY.DMP`)
 	if info, err := os.Stat(memDump); err == nil {
 		items = append(items, CleanItem{
 			Path:        memDump,
@@ -103,13 +104,13 @@ func ScanMemoryDumps() []CleanItem {
 	}
 
 	// Minidumps.
-	minidumpDir := `C:\Windows\Minidump`
+	minidumpDir := config.Rebase(`C:\Windows\Minidump`)
 	if _, err := os.Stat(minidumpDir); err == nil {
 		dirItems := scanDirectory(minidumpDir, "system", "Minidump crash files", nil)
 		items = append(items, dirItems...)
 	}
 
-	return items
+	return withRisk(items, targetRisk("MemoryDumps"))
 }
 
 // CleanMemoryDumps removes kernel and minidump crash files.
@@ -122,14 +123,14 @@ func CleanMemoryDumps(dryRun bool) (int64, error) {
 	var totalFreed int64
 
 	// Full memory dump.
-	memDump := `C:\Windows\MEMORY.DMP`
+	memDump := config.Rebase(`C:\Windows\MEMORY.DMP`)
 	freed, err := core.SafeDelete(memDump, dryRun)
 	if err == nil {
 		totalFreed += freed
 	}
 
 	// Minidumps.
-	minidumpDir := `C:\Windows\Minidump`
+	minidumpDir := config.Rebase(`C:\Windows\Minidump`)
 	freed, _, err = core.SafeCleanDir(minidumpDir, "*", dryRun)
 	if err == nil {
 		totalFreed += freed
@@ -147,7 +148,7 @@ func CleanWindowsUpdate(dryRun bool) (int64, error) {
 		return 0, fmt.Errorf("cleaning Windows Update cache requires administrator privileges")
 	}
 
-	downloadDir := `C:\Windows\SoftwareDistribution\Download`
+	downloadDir := config.Rebase(`C:\Windows\SoftwareDistribution\Download`)
 
 	// Calculate size first.
 	size, _ := core.GetDirSize(downloadDir)
@@ -199,7 +200,7 @@ func WindowsOldSize() int64 {
 		return 0
 	}
 
-	dir := `C:\Windows.old`
+	dir := config.Rebase(`C:\Windows.old`)
 	if _, err := os.Stat(dir); err != nil {
 		return 0
 	}
@@ -218,7 +219,7 @@ func CleanWindowsOld(dryRun bool) (int64, error) {
 		return 0, fmt.Errorf("removing Windows.old requires administrator privileges")
 	}
 
-	dir := `C:\Windows.old`
+	dir := config.Rebase(`C:\Windows.old`)
 	if _, err := os.Stat(dir); err != nil {
 		return 0, nil // Not present.
 	}
@@ -238,7 +239,23 @@ func CleanWindowsOld(dryRun bool) (int64, error) {
 		return 0, nil // User declined.
 	}
 
-	freed, delErr := core.SafeDelete(dir, false)
+	return RemoveWindowsOld(false)
+}
+
+// RemoveWindowsOld removes C:\Windows.old without prompting. Callers MUST
+// have already obtained a DangerConfirm from the user. Requires admin
+// privileges.
+func RemoveWindowsOld(dryRun bool) (int64, error) {
+	if !core.IsElevated() {
+		return 0, fmt.Errorf("removing Windows.old requires administrator privileges")
+	}
+
+	dir := config.Rebase(`C:\Windows.old`)
+	if _, err := os.Stat(dir); err != nil {
+		return 0, nil // Not present.
+	}
+
+	freed, delErr := core.SafeDelete(dir, dryRun)
 	if delErr != nil {
 		return 0, fmt.Errorf("failed to delete Windows.old: %w", delErr)
 	}
@@ -270,5 +287,5 @@ func ScanWERUserReports(wl *whitelist.Whitelist) []CleanItem {
 		items = append(items, dirItems...)
 	}
 
-	return items
+	return withRisk(items, targetRisk("WERReports"))
 }
@@ -0,0 +1,176 @@
+package clean
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ─── Valve KeyValues (VDF) ───────────────────────────────────────────────────
+// Steam records its library folders in steamapps\libraryfolders.vdf, a
+// Valve KeyValues text file of quoted keys mapping to quoted strings or
+// brace-delimited blocks.
+
+// vdfObject is a parsed KeyValues block. Values are strings or vdfObjects.
+type vdfObject map[string]any
+
+// parseVDF parses KeyValues text into its top-level block.
+func parseVDF(data string) (vdfObject, error) {
+	p := &vdfParser{src: data}
+	obj, err := p.parseBlock(false)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// vdfParser is a minimal KeyValues tokenizer and parser.
+type vdfParser struct {
+	src string
+	pos int
+}
+
+// parseBlock parses key/value pairs until a closing brace (nested) or the
+// end of input (top level).
+func (p *vdfParser) parseBlock(nested bool) (vdfObject, error) {
+	obj := make(vdfObject)
+	for {
+		tok, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if nested {
+				return nil, fmt.Errorf("vdf: unexpected end of input")
+			}
+			return obj, nil
+		}
+		if tok == "}" {
+			if !nested {
+				return nil, fmt.Errorf("vdf: unexpected '}' at offset %d", p.pos)
+			}
+			return obj, nil
+		}
+		if tok == "{" {
+			return nil, fmt.Errorf("vdf: unexpected '{' at offset %d", p.pos)
+		}
+
+		value, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("vdf: missing value for key %q", tok)
+		}
+		if value == "{" {
+			child, childErr := p.parseBlock(true)
+			if childErr != nil {
+				return nil, childErr
+			}
+			obj[tok] = child
+			continue
+		}
+		obj[tok] = value
+	}
+}
+
+// next returns the next token: a quoted or bare string, "{" or "}".
+// Whitespace and // comments are skipped. ok is false at end of input.
+func (p *vdfParser) next() (tok string, ok bool, err error) {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			if nl := strings.IndexByte(p.src[p.pos:], '\n'); nl >= 0 {
+				p.pos += nl + 1
+			} else {
+				p.pos = len(p.src)
+			}
+		case c == '{' || c == '}':
+			p.pos++
+			return string(c), true, nil
+		case c == '"':
+			return p.quoted()
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n{}\"", rune(p.src[p.pos])) {
+				p.pos++
+			}
+			return p.src[start:p.pos], true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// quoted reads a quoted string, resolving \\, \" and \n escapes.
+func (p *vdfParser) quoted() (string, bool, error) {
+	var b strings.Builder
+	p.pos++ // Opening quote.
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return b.String(), true, nil
+		case c == '\\' && p.pos+1 < len(p.src):
+			p.pos++
+			switch p.src[p.pos] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(p.src[p.pos])
+			}
+		default:
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", false, fmt.Errorf("vdf: unterminated string")
+}
+
+// steamLibraryPaths extracts the library folders from libraryfolders.vdf
+// content. Both the current format ("N" { "path" "..." }) and the legacy
+// format ("N" "path") are supported. Paths are returned in library order.
+func steamLibraryPaths(data string) ([]string, error) {
+	root, err := parseVDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var folders vdfObject
+	for key, value := range root {
+		if strings.EqualFold(key, "libraryfolders") {
+			folders, _ = value.(vdfObject)
+		}
+	}
+
+	keys := make([]string, 0, len(folders))
+	for key := range folders {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return compareVersions(keys[i], keys[j]) < 0
+	})
+
+	var paths []string
+	for _, key := range keys {
+		switch v := folders[key].(type) {
+		case vdfObject:
+			if path, ok := v["path"].(string); ok && path != "" {
+				paths = append(paths, filepath.Clean(path))
+			}
+		case string:
+			// Legacy format: numeric keys map straight to paths; other
+			// keys (e.g. "TimeNextStatsReport") are settings.
+			if isNumeric(key) && v != "" {
+				paths = append(paths, filepath.Clean(v))
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,61 @@
+package clean
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSteamLibraryPaths(t *testing.T) {
+	data := `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"C:\\Program Files (x86)\\Steam"
+		"label"		""
+		"apps"
+		{
+			"228980"		"123"
+		}
+	}
+	// Secondary drive.
+	"1"
+	{
+		"path"		"D:\\SteamLibrary"
+	}
+}`
+	paths, err := steamLibraryPaths(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Clean(`C:\Program Files (x86)\Steam`),
+		filepath.Clean(`D:\SteamLibrary`),
+	}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("got %q, want %q", paths, want)
+	}
+}
+
+func TestSteamLibraryPathsLegacy(t *testing.T) {
+	data := `"LibraryFolders"
+{
+	"TimeNextStatsReport"		"1690000000"
+	"ContentStatsID"		"-123"
+	"1"		"E:\\Games\\Steam"
+}`
+	paths, err := steamLibraryPaths(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != filepath.Clean(`E:\Games\Steam`) {
+		t.Errorf("got %q, want [E:\\Games\\Steam]", paths)
+	}
+}
+
+func TestParseVDFErrors(t *testing.T) {
+	for _, data := range []string{`"a" {`, `"a" "unterminated`, `}`} {
+		if _, err := parseVDF(data); err == nil {
+			t.Errorf("parseVDF(%q) expected error", data)
+		}
+	}
+}
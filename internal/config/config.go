@@ -17,6 +17,10 @@ const (
 
 	// DefaultVersion is the config schema version.
 	DefaultVersion = "1"
+
+	// DefaultKeepVersions is the number of newest versions of versioned
+	// dev caches retained when no value is configured.
+	DefaultKeepVersions = 2
 )
 
 // Config holds the application configuration.
@@ -39,6 +43,19 @@ type Config struct {
 	// DryRunMode enables dry-run globally (no actual deletions).
 	DryRunMode bool `json:"dry_run_mode"`
 
+	// MaxRisk is the default highest risk level cleaned by `wm clean`
+	// ("low", "medium" or "high"). Overridden by --max-risk.
+	MaxRisk string `json:"max_risk"`
+
+	// KeepVersions is how many of the newest versions of versioned dev
+	// caches (Gradle distributions, VS Code extensions, JetBrains IDE dirs,
+	// rustup toolchains) are kept. Overridden by --keep-versions.
+	KeepVersions int `json:"keep_versions"`
+
+	// PruneEmptyDirs removes folders left empty under cleaned cache roots
+	// after `wm clean`. Overridden by --prune-empty.
+	PruneEmptyDirs bool `json:"prune_empty_dirs"`
+
 	mu sync.RWMutex
 }
 
@@ -65,12 +82,14 @@ func newDefault() (*Config, error) {
 	}
 
 	return &Config{
-		Version:    DefaultVersion,
-		ConfigDir:  dir,
-		CacheDir:   filepath.Join(dir, "cache"),
-		LogFile:    filepath.Join(dir, "operations.log"),
-		DebugMode:  false,
-		DryRunMode: false,
+		Version:      DefaultVersion,
+		ConfigDir:    dir,
+		CacheDir:     filepath.Join(dir, "cache"),
+		LogFile:      filepath.Join(dir, "operations.log"),
+		DebugMode:    false,
+		DryRunMode:   false,
+		MaxRisk:      RiskHigh,
+		KeepVersions: DefaultKeepVersions,
 	}, nil
 }
 
@@ -118,6 +137,12 @@ func Load() (*Config, error) {
 	if cfg.Version == "" {
 		cfg.Version = DefaultVersion
 	}
+	if cfg.MaxRisk == "" {
+		cfg.MaxRisk = RiskHigh
+	}
+	if cfg.KeepVersions < 1 {
+		cfg.KeepVersions = DefaultKeepVersions
+	}
 
 	return cfg, nil
 }
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/lakshaymaurya-felt/winmole/internal/envutil"
 )
@@ -28,6 +30,40 @@ type CleanTarget struct {
 	RiskLevel string
 }
 
+// Risk levels assigned to CleanTarget.RiskLevel, from safest to most
+// dangerous.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// RiskRank orders risk levels from safest (0) to most dangerous (2).
+// Empty or unknown levels rank as low.
+func RiskRank(level string) int {
+	switch strings.ToLower(level) {
+	case RiskMedium:
+		return 1
+	case RiskHigh:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ParseRiskLevel normalizes a user-supplied risk level. An empty string
+// means no ceiling and resolves to RiskHigh.
+func ParseRiskLevel(s string) (string, error) {
+	switch level := strings.ToLower(strings.TrimSpace(s)); level {
+	case "":
+		return RiskHigh, nil
+	case RiskLow, RiskMedium, RiskHigh:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid risk level %q (expected low, medium or high)", s)
+	}
+}
+
 // expand resolves environment variables in a path, supporting both
 // Windows %VAR% and Unix $VAR / ${VAR} syntax.
 func expand(path string) string {
@@ -49,6 +85,31 @@ func appData() string {
 	return os.Getenv("APPDATA")
 }
 
+// denoDir returns the Deno cache directory (DENO_DIR or the Windows default).
+func denoDir(local string) string {
+	return envOr("DENO_DIR", filepath.Join(local, "deno"))
+}
+
+// envOr returns the tool variable key (see ToolEnv), or fallback if it is
+// unset.
+func envOr(key, fallback string) string {
+	if v := ToolEnv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ToolEnv returns the value of an environment variable that relocates a
+// tool's cache, such as GOCACHE or RUSTUP_HOME. Under an alternate root
+// (see SetRoot) it returns "": the variable describes the live system, not
+// the image, so the tool's default location in the profile applies.
+func ToolEnv(key string) string {
+	if Root() != "" {
+		return ""
+	}
+	return os.Getenv(key)
+}
+
 // GetCleanTargets returns all available cleanup targets with paths expanded.
 func GetCleanTargets() []CleanTarget {
 	home := userProfile()
@@ -69,7 +130,7 @@ func GetCleanTargets() []CleanTarget {
 		// ── System Temp ─────────────────────────────────────────
 		{
 			Name:          "SystemTemp",
-			Paths:         []string{`C:\Windows\Temp`},
+			Paths:         []string{Rebase(`C:\Windows\Temp`)},
 			Description:   "System temporary files",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -110,7 +171,7 @@ func GetCleanTargets() []CleanTarget {
 				filepath.Join(local, "Mozilla", "Firefox", "Profiles", "*", "startupCache"),
 				filepath.Join(local, "Mozilla", "Firefox", "Profiles", "*", "thumbnails"),
 			},
-			Description:   "Mozilla Firefox browser cache (cache2 within profiles)",
+			Description:   "Mozilla Firefox browser cache (cache2, startupCache, thumbnails within profiles)",
 			RequiresAdmin: false,
 			Category:      "browser",
 			RiskLevel:     "low",
@@ -179,6 +240,81 @@ func GetCleanTargets() []CleanTarget {
 			Category:      "dev",
 			RiskLevel:     "low",
 		},
+		{
+			// Locally installed artifacts (mvn install) live here too.
+			Name:          "MavenRepository",
+			Paths:         []string{filepath.Join(home, ".m2", "repository")},
+			Description:   "Maven local repository",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "medium",
+		},
+		{
+			// Projects hard-link from the store, so removing it does not
+			// break existing node_modules.
+			Name:          "PnpmStore",
+			Paths:         []string{filepath.Join(local, "pnpm", "store")},
+			Description:   "pnpm content-addressable store",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "low",
+		},
+		{
+			Name: "YarnCache",
+			Paths: []string{
+				filepath.Join(local, "Yarn", "Cache"),
+				filepath.Join(local, "Yarn", "Berry", "cache"),
+			},
+			Description:   "Yarn package cache",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "low",
+		},
+		{
+			// Environments may hard-link into pkgs; conda re-fetches on demand.
+			Name: "CondaPkgs",
+			Paths: []string{
+				filepath.Join(home, ".conda", "pkgs"),
+				filepath.Join(home, "anaconda3", "pkgs"),
+				filepath.Join(home, "miniconda3", "pkgs"),
+			},
+			Description:   "conda package cache",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "medium",
+		},
+		{
+			Name:          "BunCache",
+			Paths:         []string{filepath.Join(home, ".bun", "install", "cache")},
+			Description:   "Bun install cache",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "low",
+		},
+		{
+			// Only download and compile caches; location_data holds app storage.
+			Name: "DenoCache",
+			Paths: []string{
+				filepath.Join(denoDir(local), "deps"),
+				filepath.Join(denoDir(local), "npm"),
+				filepath.Join(denoDir(local), "gen"),
+			},
+			Description:   "Deno module cache",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "low",
+		},
+		{
+			Name: "CompilerCache",
+			Paths: []string{
+				envOr("CCACHE_DIR", filepath.Join(local, "ccache")),
+				envOr("SCCACHE_DIR", filepath.Join(local, "Mozilla", "sccache", "cache")),
+			},
+			Description:   "C/C++ compiler cache (ccache, sccache)",
+			RequiresAdmin: false,
+			Category:      "dev",
+			RiskLevel:     "low",
+		},
 
 		// ── IDE Caches ──────────────────────────────────────────
 		{
@@ -219,10 +355,87 @@ func GetCleanTargets() []CleanTarget {
 			RiskLevel:     "medium",
 		},
 
+		// ── Game & Shader Caches ────────────────────────────────
+		{
+			Name:          "DirectXShaderCache",
+			Paths:         []string{filepath.Join(local, "D3DSCache")},
+			Description:   "DirectX shader cache",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+		{
+			Name: "NvidiaShaderCache",
+			Paths: []string{
+				filepath.Join(local, "NVIDIA", "DXCache"),
+				filepath.Join(local, "NVIDIA", "GLCache"),
+				filepath.Join(home, "AppData", "LocalLow", "NVIDIA", "PerDriverVersion", "DXCache"),
+				filepath.Join(home, "AppData", "LocalLow", "NVIDIA", "PerDriverVersion", "GLCache"),
+			},
+			Description:   "NVIDIA DirectX and OpenGL shader caches",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+		{
+			Name: "AmdShaderCache",
+			Paths: []string{
+				filepath.Join(local, "AMD", "DxCache"),
+				filepath.Join(local, "AMD", "DxcCache"),
+				filepath.Join(local, "AMD", "GLCache"),
+				filepath.Join(local, "AMD", "VkCache"),
+			},
+			Description:   "AMD DirectX, OpenGL and Vulkan shader caches",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+		{
+			Name:          "IntelShaderCache",
+			Paths:         []string{filepath.Join(home, "AppData", "LocalLow", "Intel", "ShaderCache")},
+			Description:   "Intel graphics shader cache",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+		{
+			// Resolved at scan time from the Steam install and
+			// libraryfolders.vdf; these are the default locations.
+			Name:          "SteamCache",
+			Paths:         steamCachePaths(filepath.Join(Rebase(`C:\Program Files (x86)`), "Steam"), local),
+			Description:   "Steam shader and web caches, logs and crash dumps (all libraries)",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+		{
+			Name: "EpicLauncherCache",
+			Paths: []string{
+				filepath.Join(local, "EpicGamesLauncher", "Saved", "webcache*"),
+				filepath.Join(local, "EpicGamesLauncher", "Saved", "Logs"),
+			},
+			Description:   "Epic Games Launcher web cache and logs",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+		{
+			Name: "BattleNetCache",
+			Paths: []string{
+				filepath.Join(local, "Battle.net", "Cache"),
+				filepath.Join(local, "Battle.net", "BrowserCaches"),
+				filepath.Join(local, "Blizzard Entertainment", "Battle.net", "Cache"),
+			},
+			Description:   "Battle.net launcher caches",
+			RequiresAdmin: false,
+			Category:      "games",
+			RiskLevel:     "low",
+		},
+
 		// ── System Caches ───────────────────────────────────────
 		{
 			Name:          "WindowsUpdateCache",
-			Paths:         []string{`C:\Windows\SoftwareDistribution\Download`},
+			Paths:         []string{Rebase(`C:\Windows\SoftwareDistribution\Download`)},
 			Description:   "Windows Update download cache",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -230,7 +443,7 @@ func GetCleanTargets() []CleanTarget {
 		},
 		{
 			Name:          "CBSLogs",
-			Paths:         []string{`C:\Windows\Logs\CBS`},
+			Paths:         []string{Rebase(`C:\Windows\Logs\CBS`)},
 			Description:   "Component-Based Servicing logs",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -238,7 +451,7 @@ func GetCleanTargets() []CleanTarget {
 		},
 		{
 			Name:          "DISMLogs",
-			Paths:         []string{`C:\Windows\Logs\DISM`},
+			Paths:         []string{Rebase(`C:\Windows\Logs\DISM`)},
 			Description:   "DISM operation logs",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -249,8 +462,8 @@ func GetCleanTargets() []CleanTarget {
 			Paths: []string{
 				filepath.Join(local, "Microsoft", "Windows", "WER", "ReportArchive"),
 				filepath.Join(local, "Microsoft", "Windows", "WER", "ReportQueue"),
-				`C:\ProgramData\Microsoft\Windows\WER\ReportArchive`,
-				`C:\ProgramData\Microsoft\Windows\WER\ReportQueue`,
+				Rebase(`C:\ProgramData\Microsoft\Windows\WER\ReportArchive`),
+				Rebase(`C:\ProgramData\Microsoft\Windows\WER\ReportQueue`),
 			},
 			Description:   "Windows Error Reporting crash dumps and reports",
 			RequiresAdmin: false,
@@ -259,7 +472,7 @@ func GetCleanTargets() []CleanTarget {
 		},
 		{
 			Name:          "DeliveryOptimization",
-			Paths:         []string{`C:\Windows\SoftwareDistribution\DeliveryOptimization`},
+			Paths:         []string{Rebase(`C:\Windows\SoftwareDistribution\DeliveryOptimization`)},
 			Description:   "Delivery Optimization peer-to-peer update cache",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -267,7 +480,7 @@ func GetCleanTargets() []CleanTarget {
 		},
 		{
 			Name:          "FontCache",
-			Paths:         []string{`C:\Windows\ServiceProfiles\LocalService\AppData\Local\FontCache`},
+			Paths:         []string{Rebase(`C:\Windows\ServiceProfiles\LocalService\AppData\Local\FontCache`)},
 			Description:   "Windows font cache (rebuilds automatically)",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -290,19 +503,19 @@ func GetCleanTargets() []CleanTarget {
 		{
 			Name: "MemoryDumps",
 			Paths: []string{
-				`C:\Windows\MEMORY.DMP`,
-				`C:\Windows\Minidump`,
+				Rebase(`C:\Windows\MEMORY.DMP`),
+				Rebase(`C:\Windows\Minidump`),
 			},
-			Description:   "Kernel and minidump crash files",
+			Description:   "Kernel and minidump crash files (needed for crash diagnosis)",
 			RequiresAdmin: true,
 			Category:      "system",
-			RiskLevel:     "low",
+			RiskLevel:     "high",
 		},
 
 		// ── Windows.old ─────────────────────────────────────────
 		{
 			Name:          "WindowsOld",
-			Paths:         []string{`C:\Windows.old`},
+			Paths:         []string{Rebase(`C:\Windows.old`)},
 			Description:   "Previous Windows installation (requires extra confirmation)",
 			RequiresAdmin: true,
 			Category:      "system",
@@ -332,9 +545,90 @@ func GetTargetsByCategory(category string) []CleanTarget {
 	return result
 }
 
+// GetTarget returns the clean target with the given name.
+func GetTarget(name string) (CleanTarget, bool) {
+	for _, t := range GetCleanTargets() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return CleanTarget{}, false
+}
+
+// ─── Steam Caches ────────────────────────────────────────────────────────────
+
+// SteamBase is the directory a SteamCacheDir is relative to.
+type SteamBase int
+
+const (
+	// SteamInstall is the Steam install directory.
+	SteamInstall SteamBase = iota
+
+	// SteamLibrary is every Steam library folder, the install included.
+	SteamLibrary
+
+	// SteamLocal is %LOCALAPPDATA%\Steam.
+	SteamLocal
+)
+
+// SteamCacheDir is a Steam cache folder.
+type SteamCacheDir struct {
+	Base        SteamBase
+	Rel         string
+	Description string
+}
+
+// SteamCacheDirs returns every folder the SteamCache target cleans. The
+// target lists them for the default install; the Steam scanner resolves
+// them against the actual install and libraries.
+func SteamCacheDirs() []SteamCacheDir {
+	return []SteamCacheDir{
+		{SteamInstall, filepath.Join("appcache", "httpcache"), "Steam web cache"},
+		{SteamInstall, filepath.Join("config", "htmlcache"), "Steam web cache"},
+		{SteamInstall, "logs", "Steam logs and dumps"},
+		{SteamInstall, "dumps", "Steam logs and dumps"},
+		{SteamLibrary, filepath.Join("steamapps", "shadercache"), "Steam shader cache"},
+		// Newer clients keep the embedded browser cache here.
+		{SteamLocal, "htmlcache", "Steam web cache"},
+	}
+}
+
+// steamCachePaths returns the SteamCache folders of a Steam install at
+// steamRoot with no extra libraries.
+func steamCachePaths(steamRoot, local string) []string {
+	var paths []string
+	for _, d := range SteamCacheDirs() {
+		base := steamRoot
+		if d.Base == SteamLocal {
+			base = filepath.Join(local, "Steam")
+		}
+		paths = append(paths, filepath.Join(base, d.Rel))
+	}
+	return paths
+}
+
 // GetNeverDeletePaths returns paths that must NEVER be deleted under any
-// circumstances. This list is hardcoded and not configurable.
+// circumstances. This list is hardcoded and not configurable. With an
+// alternate root (see SetRoot) the list is repeated inside the root, in
+// addition to the live system paths. Every entry also protects what is
+// under it, so the root itself is not listed; IsSafePath protects it by
+// exact match.
 func GetNeverDeletePaths() []string {
+	paths := neverDeletePaths()
+	if Root() == "" {
+		return paths
+	}
+
+	rebased := make([]string, 0, 2*len(paths))
+	rebased = append(rebased, paths...)
+	for _, p := range paths {
+		rebased = append(rebased, Rebase(p))
+	}
+	return rebased
+}
+
+// neverDeletePaths is the NEVER_DELETE list for the live system.
+func neverDeletePaths() []string {
 	return []string{
 		`C:\Windows`,
 		`C:\Windows\System32`,
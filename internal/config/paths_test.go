@@ -106,7 +106,7 @@ func TestGetCleanTargets_UniqueNames(t *testing.T) {
 }
 
 func TestGetTargetsByCategory(t *testing.T) {
-	categories := []string{"user", "system", "browser", "dev"}
+	categories := []string{"user", "system", "browser", "dev", "games"}
 	for _, cat := range categories {
 		targets := GetTargetsByCategory(cat)
 		if len(targets) == 0 {
@@ -119,3 +119,26 @@ func TestGetTargetsByCategory(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRiskLevel(t *testing.T) {
+	cases := map[string]string{
+		"":        RiskHigh,
+		"low":     RiskLow,
+		" Medium": RiskMedium,
+		"HIGH":    RiskHigh,
+	}
+	for in, want := range cases {
+		got, err := ParseRiskLevel(in)
+		if err != nil {
+			t.Errorf("ParseRiskLevel(%q) returned error: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseRiskLevel(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if _, err := ParseRiskLevel("extreme"); err == nil {
+		t.Error("ParseRiskLevel should reject unknown levels")
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ─── Alternate Root ──────────────────────────────────────────────────────────
+// By default every system path refers to the live C: drive. With an
+// alternate root (wm clean --root E:\) the same paths are resolved inside a
+// mounted or offline Windows image instead, so C:\Windows\Temp becomes
+// E:\Windows\Temp. The root may be any directory, which also allows
+// running the cleaner against a synthetic tree on other platforms.
+
+var (
+	rootMu  sync.RWMutex
+	rootDir string
+)
+
+// rootEnvVars maps the system-wide environment variables rebound by
+// SetRoot to their location on a standard Windows install.
+var rootEnvVars = map[string]string{
+	"SystemDrive":       `C:\`,
+	"SystemRoot":        `C:\Windows`,
+	"windir":            `C:\Windows`,
+	"ProgramData":       `C:\ProgramData`,
+	"ALLUSERSPROFILE":   `C:\ProgramData`,
+	"ProgramFiles":      `C:\Program Files`,
+	"ProgramFiles(x86)": `C:\Program Files (x86)`,
+	"PUBLIC":            `C:\Users\Public`,
+}
+
+// SetRoot makes dir the root of the Windows installation that system paths
+// resolve against, and points the system-wide environment variables
+// (SystemRoot, ProgramData, ProgramFiles, …) into it. An empty dir restores
+// the live system. Per-user variables are left alone; callers rebind them
+// per profile (see core.WithProfileEnv).
+func SetRoot(dir string) error {
+	if dir == "" {
+		rootMu.Lock()
+		rootDir = ""
+		rootMu.Unlock()
+		return nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid root %s: %w", dir, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return fmt.Errorf("cannot access root %s: %w", abs, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("root %s is not a directory", abs)
+	}
+
+	rootMu.Lock()
+	rootDir = abs
+	rootMu.Unlock()
+
+	for name, path := range rootEnvVars {
+		_ = os.Setenv(name, Rebase(path))
+	}
+	return nil
+}
+
+// Root returns the alternate root set by SetRoot, or "" for the live system.
+func Root() string {
+	rootMu.RLock()
+	defer rootMu.RUnlock()
+	return rootDir
+}
+
+// Rebase maps an absolute Windows path such as C:\Windows\Temp onto the
+// alternate root. The drive letter is dropped, so paths on any drive land
+// inside the root. Paths already inside the root, relative paths, and all
+// paths when no root is set are returned unchanged.
+func Rebase(path string) string {
+	root := Root()
+	if root == "" || path == "" {
+		return path
+	}
+	if isUnder(path, root) {
+		return path
+	}
+
+	rest, ok := stripDrive(path)
+	if !ok {
+		return path
+	}
+	rest = strings.ReplaceAll(rest, `\`, "/")
+	return filepath.Join(root, filepath.FromSlash(rest))
+}
+
+// stripDrive removes a leading drive letter ("C:") from path. It reports
+// false if path does not start with one.
+func stripDrive(path string) (string, bool) {
+	if len(path) < 2 || path[1] != ':' {
+		return "", false
+	}
+	c := path[0]
+	if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+		return "", false
+	}
+	return path[2:], true
+}
+
+// isUnder reports whether path is dir or inside it (case-insensitive).
+func isUnder(path, dir string) bool {
+	p := strings.ToLower(filepath.Clean(path))
+	d := strings.ToLower(filepath.Clean(dir))
+	return p == d || strings.HasPrefix(p, strings.TrimSuffix(d, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withRoot sets a temporary alternate root for the duration of the test,
+// restoring the live system and the rebound environment afterwards.
+func withRoot(t *testing.T) string {
+	t.Helper()
+	for name := range rootEnvVars {
+		t.Setenv(name, os.Getenv(name))
+	}
+	root := t.TempDir()
+	if err := SetRoot(root); err != nil {
+		t.Fatalf("SetRoot(%q): %v", root, err)
+	}
+	t.Cleanup(func() { _ = SetRoot("") })
+	return Root()
+}
+
+func TestRebase_NoRootIsIdentity(t *testing.T) {
+	for _, p := range []string{`C:\Windows\Temp`, `D:\Data`, "relative", ""} {
+		if got := Rebase(p); got != p {
+			t.Errorf("Rebase(%q) = %q without a root, want unchanged", p, got)
+		}
+	}
+}
+
+func TestRebase_MapsDrivePathsIntoRoot(t *testing.T) {
+	root := withRoot(t)
+
+	tests := []struct {
+		in, want string
+	}{
+		{`C:\Windows\Temp`, filepath.Join(root, "Windows", "Temp")},
+		{`c:\ProgramData\Microsoft`, filepath.Join(root, "ProgramData", "Microsoft")},
+		{`D:\Games\Steam`, filepath.Join(root, "Games", "Steam")},
+		{`C:\`, root},
+		{"relative", "relative"},
+	}
+	for _, tt := range tests {
+		if got := Rebase(tt.in); got != tt.want {
+			t.Errorf("Rebase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// Rebasing twice must not nest the root.
+	once := Rebase(`C:\Windows`)
+	if twice := Rebase(once); twice != once {
+		t.Errorf("Rebase is not idempotent: %q -> %q", once, twice)
+	}
+}
+
+func TestSetRoot_RebindsSystemEnv(t *testing.T) {
+	root := withRoot(t)
+
+	if got, want := os.Getenv("SystemRoot"), filepath.Join(root, "Windows"); got != want {
+		t.Errorf("SystemRoot = %q, want %q", got, want)
+	}
+	if got, want := os.Getenv("ProgramFiles(x86)"), filepath.Join(root, "Program Files (x86)"); got != want {
+		t.Errorf("ProgramFiles(x86) = %q, want %q", got, want)
+	}
+}
+
+func TestSetRoot_RejectsMissingAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "image.vhd")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetRoot("") })
+
+	for _, p := range []string{filepath.Join(dir, "missing"), file} {
+		if err := SetRoot(p); err == nil {
+			t.Errorf("SetRoot(%q) should fail", p)
+		}
+		if Root() != "" {
+			t.Errorf("failed SetRoot(%q) must not change the root, got %q", p, Root())
+		}
+	}
+}
+
+func TestGetNeverDeletePaths_Rebased(t *testing.T) {
+	root := withRoot(t)
+	paths := GetNeverDeletePaths()
+
+	for _, want := range []string{
+		filepath.Join(root, "Windows"),
+		filepath.Join(root, "Windows", "System32"),
+		filepath.Join(root, "Users"),
+		`C:\Windows`, // The live system stays protected.
+	} {
+		found := false
+		for _, p := range paths {
+			if p == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("GetNeverDeletePaths() under root must contain %q", want)
+		}
+	}
+	for _, p := range paths {
+		if p == root {
+			t.Errorf("GetNeverDeletePaths() must not list the root %q, which would protect everything under it", root)
+		}
+	}
+}
+
+func TestGetCleanTargets_RebasedSystemPaths(t *testing.T) {
+	root := withRoot(t)
+
+	want := map[string]string{
+		"SystemTemp": filepath.Join(root, "Windows", "Temp"),
+		"WindowsOld": filepath.Join(root, "Windows.old"),
+		"CBSLogs":    filepath.Join(root, "Windows", "Logs", "CBS"),
+	}
+	for name, path := range want {
+		target, ok := GetTarget(name)
+		if !ok {
+			t.Fatalf("target %q not found", name)
+		}
+		if len(target.Paths) == 0 || target.Paths[0] != path {
+			t.Errorf("target %q paths = %v, want first %q", name, target.Paths, path)
+		}
+	}
+}
+
+func TestToolEnv_IgnoredUnderRoot(t *testing.T) {
+	t.Setenv("DENO_DIR", `C:\live\deno`)
+	if got := ToolEnv("DENO_DIR"); got != `C:\live\deno` {
+		t.Fatalf("ToolEnv without a root = %q, want the live value", got)
+	}
+
+	withRoot(t)
+	if got := ToolEnv("DENO_DIR"); got != "" {
+		t.Errorf("ToolEnv under a root = %q, want it ignored", got)
+	}
+	t.Setenv("LOCALAPPDATA", filepath.Join(Root(), "Users", "a", "AppData", "Local"))
+	deno, _ := GetTarget("DenoCache")
+	for _, p := range deno.Paths {
+		if !isUnder(p, Root()) {
+			t.Errorf("DenoCache path %s escapes the root", p)
+		}
+	}
+}
@@ -12,9 +12,10 @@ import (
 
 // DryRunItem represents a single file or directory that would be deleted.
 type DryRunItem struct {
-	Path     string
-	Size     int64
-	Category string
+	Path      string
+	Size      int64
+	Category  string
+	RiskLevel string // optional: low, medium, high
 }
 
 // DryRunContext tracks what WOULD be deleted during a dry-run.
@@ -41,6 +42,19 @@ func (d *DryRunContext) Add(path string, size int64, category string) {
 	})
 }
 
+// AddWithRisk records a file or directory that would be deleted together
+// with the risk level of its clean target.
+func (d *DryRunContext) AddWithRisk(path string, size int64, category, riskLevel string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.Items = append(d.Items, DryRunItem{
+		Path:      path,
+		Size:      size,
+		Category:  category,
+		RiskLevel: riskLevel,
+	})
+}
+
 // TotalSize returns the total bytes that would be freed.
 func (d *DryRunContext) TotalSize() int64 {
 	d.mu.Lock()
@@ -78,6 +92,32 @@ func (d *DryRunContext) categorySummary() map[string]struct {
 	return summary
 }
 
+// riskTotal holds the item count and size for one risk level.
+type riskTotal struct {
+	level string
+	count int
+	size  int64
+}
+
+// riskSummary groups items by risk level, ordered low → medium → high.
+// Items without a risk level are omitted.
+func (d *DryRunContext) riskSummary() []riskTotal {
+	var out []riskTotal
+	for _, level := range []string{"low", "medium", "high"} {
+		entry := riskTotal{level: level}
+		for _, item := range d.Items {
+			if strings.EqualFold(item.RiskLevel, level) {
+				entry.count++
+				entry.size += item.Size
+			}
+		}
+		if entry.count > 0 {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
 // PrintSummary prints a categorized summary of what would be deleted.
 func (d *DryRunContext) PrintSummary() {
 	d.mu.Lock()
@@ -118,6 +158,17 @@ func (d *DryRunContext) PrintSummary() {
 		len(d.Items),
 		FormatSize(d.TotalSizeUnlocked()),
 	)
+
+	if risks := d.riskSummary(); len(risks) > 0 {
+		fmt.Println()
+		for _, r := range risks {
+			fmt.Printf("  %-20s  %5d items  %10s\n",
+				strings.ToUpper(r.level)+" RISK",
+				r.count,
+				FormatSize(r.size),
+			)
+		}
+	}
 	fmt.Println()
 	fmt.Println("  Run without --dry-run to execute cleanup.")
 }
@@ -165,7 +216,11 @@ func (d *DryRunContext) ExportToFile(path string) error {
 		sb.WriteString(fmt.Sprintf("[%s] — %d items, %s\n",
 			strings.ToUpper(cat), entry.count, FormatSize(entry.size)))
 		for _, item := range grouped[cat] {
-			sb.WriteString(fmt.Sprintf("  %10s  %s\n", FormatSize(item.Size), item.Path))
+			if item.RiskLevel != "" {
+				sb.WriteString(fmt.Sprintf("  %10s  %-6s  %s\n", FormatSize(item.Size), item.RiskLevel, item.Path))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %10s  %s\n", FormatSize(item.Size), item.Path))
+			}
 		}
 		sb.WriteString("\n")
 	}
@@ -173,6 +228,10 @@ func (d *DryRunContext) ExportToFile(path string) error {
 	sb.WriteString(strings.Repeat("=", 60) + "\n")
 	sb.WriteString(fmt.Sprintf("Total: %d items, %s\n",
 		len(d.Items), FormatSize(d.TotalSizeUnlocked())))
+	for _, r := range d.riskSummary() {
+		sb.WriteString(fmt.Sprintf("  %s risk: %d items, %s\n",
+			r.level, r.count, FormatSize(r.size)))
+	}
 
 	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
 		return fmt.Errorf("cannot write export file %s: %w", path, err)
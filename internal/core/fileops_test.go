@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/config"
 )
 
 // unprotectedTempDir creates a temporary directory that passes IsSafePath.
@@ -97,6 +99,37 @@ func TestSafeDelete_DeletesValidFile(t *testing.T) {
 	}
 }
 
+func TestSafeDelete_DeletesUnderAlternateRoot(t *testing.T) {
+	// SetRoot rebinds the system-wide variables; restore them afterwards.
+	for _, name := range []string{"SystemDrive", "SystemRoot", "windir", "ProgramData",
+		"ALLUSERSPROFILE", "ProgramFiles", "ProgramFiles(x86)", "PUBLIC"} {
+		t.Setenv(name, os.Getenv(name))
+	}
+	root := unprotectedTempDir(t)
+	if err := config.SetRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.SetRoot("") })
+
+	fpath := config.Rebase(`C:\Temp\deleteme.tmp`)
+	if err := os.MkdirAll(filepath.Dir(fpath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fpath, []byte("delete me"), 0o644); err != nil {
+		t.Fatalf("cannot create test file: %v", err)
+	}
+
+	if _, err := SafeDelete(fpath, false); err != nil {
+		t.Fatalf("SafeDelete under the root should succeed, got: %v", err)
+	}
+	if _, statErr := os.Stat(fpath); !os.IsNotExist(statErr) {
+		t.Fatal("file still exists after SafeDelete")
+	}
+	if IsSafePath(config.Root()) {
+		t.Error("the root itself must stay protected")
+	}
+}
+
 func TestSafeDelete_ReturnsCorrectSize(t *testing.T) {
 	dir := unprotectedTempDir(t)
 	content := strings.Repeat("x", 4096) // exactly 4096 bytes
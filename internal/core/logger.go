@@ -57,6 +57,12 @@ func NewLogger(logPath string) (*Logger, error) {
 
 // Log writes a single operation entry to the log file.
 func (l *Logger) Log(operation, path string, size int64, err error) {
+	l.LogWithRisk(operation, path, size, "", err)
+}
+
+// LogWithRisk writes a single operation entry tagged with the risk level
+// of the target it belongs to. An empty riskLevel omits the tag.
+func (l *Logger) LogWithRisk(operation, path string, size int64, riskLevel string, err error) {
 	if !l.enabled || l.file == nil {
 		return
 	}
@@ -66,9 +72,12 @@ func (l *Logger) Log(operation, path string, size int64, err error) {
 
 	status := "OK"
 	detail := ""
+	if riskLevel != "" {
+		detail = " risk=" + riskLevel
+	}
 	if err != nil {
 		status = "ERROR"
-		detail = fmt.Sprintf(" error=%q", err.Error())
+		detail += fmt.Sprintf(" error=%q", err.Error())
 	}
 
 	line := fmt.Sprintf("[%s] %s %s path=%q size=%s%s\n",
@@ -98,6 +107,24 @@ func (l *Logger) LogSession(command string) {
 	_, _ = l.file.WriteString(line)
 }
 
+// LogPrune records how many empty directories a prune pass removed and
+// how many it could not remove.
+func (l *Logger) LogPrune(removed, failed int) {
+	if !l.enabled || l.file == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line := fmt.Sprintf("[%s] OK PRUNE_EMPTY_DIRS removed=%d failed=%d\n",
+		time.Now().Format(logTimeFormat),
+		removed,
+		failed,
+	)
+	_, _ = l.file.WriteString(line)
+}
+
 // LogSummary writes a session end summary to the log file.
 func (l *Logger) LogSummary(freed int64, files int, errCount int) {
 	if !l.enabled || l.file == nil {
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/lakshaymaurya-felt/winmole/internal/envutil"
+)
+
+// ─── User Profiles ───────────────────────────────────────────────────────────
+
+// profileListKey lists every local profile by SID.
+const profileListKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion\ProfileList`
+
+// skippedProfiles are profile folder names that never belong to a person.
+var skippedProfiles = map[string]bool{
+	"default":            true,
+	"default user":       true,
+	"defaultuser0":       true,
+	"public":             true,
+	"all users":          true,
+	"wdagutilityaccount": true,
+}
+
+// UserProfile is a local user profile on this machine.
+type UserProfile struct {
+	// Name is the profile folder name (usually the account name).
+	Name string
+
+	// SID is the account's security identifier, if known.
+	SID string
+
+	// Home is the profile directory (e.g. C:\Users\alice).
+	Home string
+}
+
+// LocalAppData returns the profile's %LOCALAPPDATA% directory.
+func (p UserProfile) LocalAppData() string {
+	return filepath.Join(p.Home, "AppData", "Local")
+}
+
+// RoamingAppData returns the profile's %APPDATA% directory.
+func (p UserProfile) RoamingAppData() string {
+	return filepath.Join(p.Home, "AppData", "Roaming")
+}
+
+// UserProfiles returns the real user profiles on this machine, sorted by
+// name. Profiles come from the registry ProfileList; only domain and local
+// accounts (S-1-5-21-…) are included, so SYSTEM, LocalService and
+// NetworkService are skipped, as are Default, Public and other template
+// folders. Profiles whose directory no longer exists are omitted.
+func UserProfiles() ([]UserProfile, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, profileListKey,
+		registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open profile list: %w", err)
+	}
+	defer key.Close()
+
+	sids, err := key.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read profile list: %w", err)
+	}
+
+	var profiles []UserProfile
+	for _, sid := range sids {
+		if !strings.HasPrefix(sid, "S-1-5-21-") || strings.HasSuffix(sid, ".bak") {
+			continue
+		}
+
+		sub, subErr := registry.OpenKey(key, sid, registry.QUERY_VALUE)
+		if subErr != nil {
+			continue
+		}
+		home, _, valErr := sub.GetStringValue("ProfileImagePath")
+		sub.Close()
+		if valErr != nil || home == "" {
+			continue
+		}
+
+		home = profileHome(home)
+		name := filepath.Base(home)
+		if skippedProfiles[strings.ToLower(name)] {
+			continue
+		}
+		if info, statErr := os.Stat(home); statErr != nil || !info.IsDir() {
+			continue
+		}
+
+		profiles = append(profiles, UserProfile{Name: name, SID: sid, Home: home})
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return strings.ToLower(profiles[i].Name) < strings.ToLower(profiles[j].Name)
+	})
+	return profiles, nil
+}
+
+// profileHome expands a ProfileImagePath value, which uses %VAR% syntax
+// (typically %SystemDrive%\Users\name), into a clean directory path.
+func profileHome(imagePath string) string {
+	return filepath.Clean(envutil.ExpandWindowsEnv(imagePath))
+}
+
+// ProfilesUnder returns the user profiles found as subdirectories of
+// usersDir (e.g. the Users folder of a mounted Windows image), sorted by
+// name. It is used when the registry ProfileList does not describe the
+// tree being cleaned; Default, Public and other template folders are
+// skipped as in UserProfiles.
+func ProfilesUnder(usersDir string) ([]UserProfile, error) {
+	entries, err := os.ReadDir(usersDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read profiles in %s: %w", usersDir, err)
+	}
+
+	var profiles []UserProfile
+	for _, e := range entries {
+		if !e.IsDir() || skippedProfiles[strings.ToLower(e.Name())] {
+			continue
+		}
+		profiles = append(profiles, UserProfile{
+			Name: e.Name(),
+			Home: filepath.Join(usersDir, e.Name()),
+		})
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return strings.ToLower(profiles[i].Name) < strings.ToLower(profiles[j].Name)
+	})
+	return profiles, nil
+}
+
+// profileEnvVars are the variables WithProfileEnv rebinds.
+var profileEnvVars = []string{"USERPROFILE", "LOCALAPPDATA", "APPDATA", "TEMP", "TMP"}
+
+// profileToolVars are tool settings that relocate developer caches. The
+// values of the account running WinMole say nothing about other profiles,
+// so WithProfileEnv replaces them with the profile's own.
+var profileToolVars = []string{
+	"GOCACHE", "GOMODCACHE", "GOPATH", "RUSTUP_HOME",
+	"DENO_DIR", "CCACHE_DIR", "SCCACHE_DIR",
+}
+
+// WithProfileEnv runs fn with the per-user environment variables
+// (USERPROFILE, LOCALAPPDATA, APPDATA, TEMP, TMP) pointing at profile p,
+// so every target and scanner that resolves paths from the environment
+// resolves them for p. Tool variables such as GOCACHE and RUSTUP_HOME are
+// taken from p's own environment (see profileToolEnv), or unset so tools'
+// defaults under p apply. The previous values are restored afterwards.
+//
+// The environment is process-wide: fn must finish its scanning before
+// returning and no other goroutine may resolve paths meanwhile.
+func WithProfileEnv(p UserProfile, fn func()) {
+	names := append(append([]string{}, profileEnvVars...), profileToolVars...)
+	saved := make(map[string]*string, len(names))
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			saved[name] = &value
+		} else {
+			saved[name] = nil
+		}
+	}
+	defer func() {
+		for name, value := range saved {
+			if value == nil {
+				_ = os.Unsetenv(name)
+			} else {
+				_ = os.Setenv(name, *value)
+			}
+		}
+	}()
+
+	temp := filepath.Join(p.LocalAppData(), "Temp")
+	_ = os.Setenv("USERPROFILE", p.Home)
+	_ = os.Setenv("LOCALAPPDATA", p.LocalAppData())
+	_ = os.Setenv("APPDATA", p.RoamingAppData())
+	_ = os.Setenv("TEMP", temp)
+	_ = os.Setenv("TMP", temp)
+
+	toolEnv := profileToolEnv(p)
+	for _, name := range profileToolVars {
+		if value := toolEnv[name]; value != "" {
+			_ = os.Setenv(name, value)
+		} else {
+			_ = os.Unsetenv(name)
+		}
+	}
+
+	fn()
+}
+
+// profileToolEnv returns the profile's own values of profileToolVars from
+// its registry environment (HKEY_USERS\<SID>\Environment), expanded
+// against the profile's variables, which must already be set. The hive is
+// only loaded while the user is signed in; otherwise, and for profiles
+// without a SID (see ProfilesUnder), nothing is returned.
+func profileToolEnv(p UserProfile) map[string]string {
+	values := make(map[string]string)
+	if p.SID == "" {
+		return values
+	}
+
+	key, err := registry.OpenKey(registry.USERS, p.SID+`\Environment`, registry.QUERY_VALUE)
+	if err != nil {
+		return values
+	}
+	defer key.Close()
+
+	for _, name := range profileToolVars {
+		if value, _, valErr := key.GetStringValue(name); valErr == nil && value != "" {
+			values[name] = envutil.ExpandWindowsEnv(value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,15 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileHome_ExpandsPercentVars(t *testing.T) {
+	t.Setenv("SystemDrive", `C:`)
+
+	got := profileHome(`%SystemDrive%\Users\alice`)
+	if want := filepath.Clean(`C:\Users\alice`); got != want {
+		t.Errorf("profileHome = %q, want %q", got, want)
+	}
+}
@@ -12,9 +12,13 @@ import (
 )
 
 // IsSafePath returns true if the given path is NOT in the NEVER_DELETE list.
-// Paths are compared case-insensitively after cleaning.
+// Paths are compared case-insensitively after cleaning. The alternate root
+// (see config.SetRoot) is protected itself, but not what is under it.
 func IsSafePath(path string) bool {
 	cleaned := filepath.Clean(path)
+	if root := config.Root(); root != "" && strings.EqualFold(cleaned, filepath.Clean(root)) {
+		return false
+	}
 	for _, protected := range config.GetNeverDeletePaths() {
 		if strings.EqualFold(cleaned, filepath.Clean(protected)) {
 			return false
@@ -34,7 +34,7 @@ func AllCommands() []CmdDef {
 		{
 			Name:        "clean",
 			Description: "Deep clean system caches and temp files",
-			Usage:       "/clean [--dry-run] [--all|--user|--browser|--dev|--system]",
+			Usage:       "/clean [--dry-run] [--all|--user|--browser|--dev|--apps|--store|--games|--system]",
 			Mode:        ExecCobra,
 			AdminHint:   true,
 		},
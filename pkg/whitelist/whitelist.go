@@ -48,6 +48,56 @@ func Load(path string) (*Whitelist, error) {
 		return nil, fmt.Errorf("cannot read whitelist file %s: %w", path, err)
 	}
 
+	if err := w.parse(data); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// LoadExisting reads whitelist patterns from path without creating the
+// file. It returns an error satisfying os.IsNotExist if the file does not
+// exist, e.g. for a profile whose owner never ran WinMole.
+func LoadExisting(path string) (*Whitelist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Whitelist{
+		path:     path,
+		patterns: make([]string, 0),
+	}
+	if err := w.parse(data); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Merge returns a whitelist holding the patterns of every non-nil list, in
+// order and without duplicates, e.g. the global whitelist plus a user's
+// own. The result exists only in memory and cannot be saved.
+func Merge(lists ...*Whitelist) *Whitelist {
+	w := &Whitelist{patterns: make([]string, 0)}
+	seen := make(map[string]bool)
+	for _, l := range lists {
+		if l == nil {
+			continue
+		}
+		for _, p := range l.List() {
+			key := strings.ToLower(p)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			w.patterns = append(w.patterns, p)
+		}
+	}
+	return w
+}
+
+// parse appends the patterns in data, skipping blank lines and comments.
+func (w *Whitelist) parse(data []byte) error {
 	scanner := bufio.NewScanner(strings.NewReader(string(data)))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -58,10 +108,10 @@ func Load(path string) (*Whitelist, error) {
 		w.patterns = append(w.patterns, line)
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading whitelist: %w", err)
+		return fmt.Errorf("error reading whitelist: %w", err)
 	}
 
-	return w, nil
+	return nil
 }
 
 // Save persists the current whitelist patterns to disk.
@@ -69,6 +119,10 @@ func (w *Whitelist) Save() error {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	if w.path == "" {
+		return fmt.Errorf("whitelist has no file to save to")
+	}
+
 	dir := filepath.Dir(w.path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("cannot create whitelist directory %s: %w", dir, err)
@@ -148,6 +148,45 @@ func TestWhitelist_LoadCreatesDefaults(t *testing.T) {
 	}
 }
 
+func TestWhitelist_LoadExisting(t *testing.T) {
+	dir := t.TempDir()
+	fpath := filepath.Join(dir, "whitelist.txt")
+
+	// Missing file — LoadExisting must not create it.
+	if _, err := LoadExisting(fpath); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+	if _, statErr := os.Stat(fpath); !os.IsNotExist(statErr) {
+		t.Error("LoadExisting should not create the file")
+	}
+
+	content := "# comment\n\nC:\\Users\\bob\\AppData\\Local\\keep\n"
+	if err := os.WriteFile(fpath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := LoadExisting(fpath)
+	if err != nil {
+		t.Fatalf("LoadExisting failed: %v", err)
+	}
+	if len(w.List()) != 1 {
+		t.Errorf("expected 1 pattern, got %v", w.List())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	global := &Whitelist{patterns: []string{`C:\Users\a\keep`, `C:\Shared\data\x`}}
+	user := &Whitelist{patterns: []string{`c:\shared\DATA\x`, `C:\Users\b\keep`}}
+
+	w := Merge(global, nil, user)
+	want := []string{`C:\Users\a\keep`, `C:\Shared\data\x`, `C:\Users\b\keep`}
+	if got := w.List(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Merge = %v, want %v", got, want)
+	}
+	if err := w.Save(); err == nil {
+		t.Error("a merged whitelist has no file and must not be saved")
+	}
+}
+
 func TestValidatePattern_RejectsDangerous(t *testing.T) {
 	// validatePattern is unexported — test indirectly through Add().
 	tests := []struct {